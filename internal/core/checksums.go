@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sapcc/go-bits/must"
+)
+
+// ToolChecksumsPath is the path of the table of known tool checksums, relative to the root of the go-makefile-maker repository.
+const ToolChecksumsPath = "internal/core/tool-checksums.json"
+
+//go:embed tool-checksums.json
+var toolChecksumsJSON []byte
+
+// DownloadedTools lists the tools that the install-* targets in the Makefile download from GitHub releases.
+var DownloadedTools = []string{"shellcheck", "typos"}
+
+// DefaultToolVersions contains the default versions of the DownloadedTools.
+// The table returned by KnownToolChecksums must contain the checksums of these versions for all ToolPlatforms.
+var DefaultToolVersions = map[string]string{
+	"shellcheck": DefaultShellcheckVersion,
+	"typos":      DefaultTyposVersion,
+}
+
+// ToolPlatforms lists the platforms for which the install-* targets download prebuilt release archives.
+// Platforms are identified as "$OS/$ARCH" like in `uname -s` and `uname -m`, but with the OS in lower case and "arm64" spelled as "aarch64".
+var ToolPlatforms = []string{"darwin/aarch64", "darwin/x86_64", "linux/aarch64", "linux/x86_64"}
+
+// ToolChecksumTable contains the SHA-256 checksums of the release archives of the DownloadedTools.
+// The keys are the tool name, the version and the platform (see ToolPlatforms), in this order.
+type ToolChecksumTable map[string]map[string]map[string]string
+
+// KnownToolChecksums returns the table of checksums that is shipped with go-makefile-maker.
+// It is updated with `go-makefile-maker --update-tool-checksums`.
+func KnownToolChecksums() ToolChecksumTable {
+	var table ToolChecksumTable
+	must.Succeed(json.Unmarshal(toolChecksumsJSON, &table))
+	return table
+}
+
+// ToolReleaseURL returns the URL of the release archive of the given tool (from DownloadedTools) for the given version and platform.
+func ToolReleaseURL(tool, version, platform string) string {
+	osName, arch, _ := strings.Cut(platform, "/")
+	switch tool {
+	case "shellcheck":
+		return fmt.Sprintf("https://github.com/koalaman/shellcheck/releases/download/%[1]s/shellcheck-%[1]s.%[2]s.%[3]s.tar.xz", version, osName, arch)
+	case "typos":
+		target := arch + "-unknown-linux-musl"
+		if osName == "darwin" {
+			target = arch + "-apple-darwin"
+		}
+		return fmt.Sprintf("https://github.com/crate-ci/typos/releases/download/%[1]s/typos-%[1]s-%[2]s.tar.gz", version, target)
+	default:
+		panic("no release archives known for tool: " + tool)
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var sha256Rx = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func TestKnownToolChecksums(t *testing.T) {
+	table := KnownToolChecksums()
+	for tool, versions := range table {
+		if !slices.Contains(DownloadedTools, tool) {
+			t.Errorf("unexpected tool %q in %s", tool, ToolChecksumsPath)
+		}
+		for version, checksums := range versions {
+			for _, platform := range ToolPlatforms {
+				if !sha256Rx.MatchString(checksums[platform]) {
+					t.Errorf("expected a SHA-256 checksum for %s %s on %s in %s, but got %q", tool, version, platform, ToolChecksumsPath, checksums[platform])
+				}
+			}
+			if len(checksums) != len(ToolPlatforms) {
+				t.Errorf("expected only the platforms %v for %s %s in %s, but got %v", ToolPlatforms, tool, version, ToolChecksumsPath, checksums)
+			}
+		}
+	}
+
+	// the checksums can only be added with network access, so this is reported, but does not fail the test
+	var missing []string
+	for _, tool := range DownloadedTools {
+		if version := DefaultToolVersions[tool]; table[tool][version] == nil {
+			missing = append(missing, tool+" "+version)
+		}
+	}
+	if len(missing) > 0 {
+		t.Skipf("no checksums are known for the default versions %v, run `go-makefile-maker --update-tool-checksums` to add them", missing)
+	}
+}
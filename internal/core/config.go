@@ -5,15 +5,11 @@ package core
 
 import (
 	_ "embed"
-	"maps"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
-	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/regexpext"
 	. "go.xyrillian.de/gg/option"
 )
@@ -24,33 +20,64 @@ import (
 //go:embed autogenerated-header.tmpl
 var AutogeneratedHeader string
 
+// ConfigurationSource is the source code of this file.
+// The JSON schema for the config file takes its descriptions from the doc comments of the types herein.
+//
+//go:embed config.go
+var ConfigurationSource string
+
 ///////////////////////////////////////////////////////////////////////////////
 // Core configuration
 
 // Configuration is the data structure that we read from the input file.
 type Configuration struct {
-	Binaries       []BinaryConfiguration        `yaml:"binaries"`
-	Coverage       CoverageConfiguration        `yaml:"coverageTest"`
-	ControllerGen  ControllerGen                `yaml:"controllerGen"`
-	Dockerfile     DockerfileConfig             `yaml:"dockerfile"`
-	EnvRc          EnvRcConfig                  `yaml:"envRc"`
+	// Binaries lists the binaries that are built by the Makefile.
+	Binaries []BinaryConfiguration `yaml:"binaries"`
+	// Coverage restricts which packages are subject to coverage testing.
+	Coverage CoverageConfiguration `yaml:"coverageTest"`
+	// ControllerGen customizes the invocation of controller-gen for Kubernetes controllers.
+	ControllerGen ControllerGen `yaml:"controllerGen"`
+	// Dockerfile configures the generated Dockerfile and .dockerignore file.
+	Dockerfile DockerfileConfig `yaml:"dockerfile"`
+	// EnvRc configures the generated .envrc file for direnv.
+	EnvRc EnvRcConfig `yaml:"envRc"`
+	// Extends lists base configs that this config is merged onto, as paths relative to this file.
+	// Later entries take precedence over earlier ones, and this file takes precedence over all of them.
+	Extends []string `yaml:"extends"`
+	// GitHubWorkflow configures the generated GitHub workflows. No workflows are generated if this section is missing.
 	GitHubWorkflow *GithubWorkflowConfiguration `yaml:"githubWorkflow"`
-	Golang         GolangConfiguration          `yaml:"golang"`
-	GolangciLint   GolangciLintConfiguration    `yaml:"golangciLint"`
-	GoReleaser     GoReleaserConfiguration      `yaml:"goReleaser"`
-	License        LicenseConfig                `yaml:"license"`
-	Makefile       MakefileConfig               `yaml:"makefile"`
-	Metadata       Metadata                     `yaml:"metadata"`
-	Nix            NixConfig                    `yaml:"nix"`
-	Renovate       RenovateConfig               `yaml:"renovate"`
-	ShellCheck     ShellCheckConfiguration      `yaml:"shellCheck"`
+	// Golang contains settings for the Go toolchain and for dependency management.
+	Golang GolangConfiguration `yaml:"golang"`
+	// GolangciLint configures golangci-lint and its generated config file.
+	GolangciLint GolangciLintConfiguration `yaml:"golangciLint"`
+	// GoReleaser configures the generated GoReleaser config file.
+	GoReleaser GoReleaserConfiguration `yaml:"goReleaser"`
+	// License contains settings for license headers and for checking the licenses of dependencies.
+	License LicenseConfig `yaml:"license"`
+	// Makefile contains settings for the generated Makefile.
+	Makefile MakefileConfig `yaml:"makefile"`
+	// Metadata contains information about the project that cannot be guessed consistently.
+	Metadata Metadata `yaml:"metadata"`
+	// Nix configures the generated shell.nix file.
+	Nix NixConfig `yaml:"nix"`
+	// Renovate configures the generated Renovate config file.
+	Renovate RenovateConfig `yaml:"renovate"`
+	// ShellCheck configures linting of shell scripts with ShellCheck.
+	ShellCheck ShellCheckConfiguration `yaml:"shellCheck"`
 	// Deprecated: use `typos` instead.
-	SpellCheck     SpellCheckConfiguration `yaml:"spellCheck"`
-	Test           TestConfiguration       `yaml:"testPackages"`
-	Typos          TyposConfiguration      `yaml:"typos"`
-	Reuse          ReuseConfiguration      `yaml:"reuse"`
-	Verbatim       string                  `yaml:"verbatim"`
-	VariableValues map[string]string       `yaml:"variables"`
+	SpellCheck SpellCheckConfiguration `yaml:"spellCheck"`
+	// Test restricts which packages are subject to testing.
+	Test TestConfiguration `yaml:"testPackages"`
+	// Tools pins the versions of the tools that are installed by the install-* targets in the Makefile.
+	Tools ToolsConfiguration `yaml:"tools"`
+	// Typos configures spell checking with typos.
+	Typos TyposConfiguration `yaml:"typos"`
+	// Reuse configures the generated REUSE.toml file.
+	Reuse ReuseConfiguration `yaml:"reuse"`
+	// Verbatim is copied into the Makefile mostly verbatim. Recipes may be indented with spaces instead of tabs.
+	Verbatim string `yaml:"verbatim"`
+	// VariableValues overrides the default values of Makefile variables like GO_BUILDFLAGS or GO_TESTENV.
+	VariableValues map[string]string `yaml:"variables"`
 }
 
 // Variable returns the value of this variable if it's overridden in the config,
@@ -68,20 +95,150 @@ func (c Configuration) Variable(name, defaultValue string) string {
 
 // BinaryConfiguration appears in type Configuration.
 type BinaryConfiguration struct {
-	Name        string `yaml:"name"`
+	// Name is the name of the binary, which is built into build/$NAME.
+	Name string `yaml:"name"`
+	// FromPackage is the Go package containing the main function, relative to the repository root.
 	FromPackage string `yaml:"fromPackage"`
-	InstallTo   string `yaml:"installTo"`
+	// InstallTo is the directory below $PREFIX that `make install` installs the binary into. If empty, the binary is not installed.
+	InstallTo string `yaml:"installTo"`
+	// BuildTags are build tags that are given to `go build -tags` for this binary only.
+	BuildTags []string `yaml:"buildTags"`
+	// CGO sets CGO_ENABLED for this binary. If unset, the default of the Go toolchain applies (or, for GoReleaser, CGO is disabled).
+	CGO Option[bool] `yaml:"cgo"`
+	// LdFlags are linker flags for this binary only, which are appended to GO_LDFLAGS.
+	LdFlags []string `yaml:"ldflags"`
+	// Env contains environment variables for `go build` for this binary only, which are appended to GO_BUILDENV.
+	Env map[string]string `yaml:"env"`
+	// BuildFlags are flags for `go build` for this binary only, which are appended to GO_BUILDFLAGS.
+	BuildFlags []string `yaml:"buildFlags"`
 }
 
 // TestConfiguration appears in type Configuration.
 type TestConfiguration struct {
-	Only   string `yaml:"only"`
+	// Only is a regex for `grep -E` that selects the packages to test.
+	Only string `yaml:"only"`
+	// Except is a regex for `grep -E` that excludes packages from testing.
+	Except string `yaml:"except"`
+	// Fuzz configures the fuzz targets that are run by `make fuzz`.
+	Fuzz FuzzConfiguration `yaml:"fuzz"`
+	// Race enables a job in the CI workflow that runs the tests with the race detector via `make check-race`.
+	Race bool `yaml:"race"`
+	// Reports lists the test reports that are written next to build/cover.out, either "junit" (build/test-report.xml) or "json" (build/test-report.json).
+	Reports []string `yaml:"reports"`
+	// Integration configures the integration tests that are run by `make check-integration`.
+	Integration IntegrationTestConfiguration `yaml:"integration"`
+}
+
+// IntegrationTestConfiguration appears in type TestConfiguration.
+type IntegrationTestConfiguration struct {
+	// Enabled adds the check-integration target to the Makefile and an integration job to the CI workflow.
+	Enabled bool `yaml:"enabled"`
+	// Tags are the build tags that select the integration tests. Defaults to ["integration"].
+	Tags Option[[]string] `yaml:"tags"`
+	// Only is a regex for `grep -E` that selects the packages with integration tests.
+	Only string `yaml:"only"`
+	// Except is a regex for `grep -E` that excludes packages from integration testing.
 	Except string `yaml:"except"`
+	// Env contains environment variables for running the integration tests.
+	Env map[string]string `yaml:"env"`
+	// Services lists the services that the integration tests need (see TestServices).
+	// In the CI workflow, they are run as service containers.
+	Services []string `yaml:"services"`
+}
+
+// GetTags returns the set build tags for integration tests or a default.
+func (i IntegrationTestConfiguration) GetTags() []string {
+	return i.Tags.UnwrapOr([]string{"integration"})
+}
+
+// TestReportFormats contains the acceptable values for TestConfiguration.Reports.
+var TestReportFormats = []string{"junit", "json"}
+
+// HasReport returns whether the given test report (one of TestReportFormats) shall be written.
+func (t TestConfiguration) HasReport(format string) bool {
+	return slices.Contains(t.Reports, format)
+}
+
+// FuzzConfiguration appears in type TestConfiguration.
+type FuzzConfiguration struct {
+	// Time is the default for FUZZTIME, i.e. how long `make fuzz` runs each fuzz target (as accepted by `go test -fuzztime`). Defaults to 30s.
+	Time Option[string] `yaml:"time"`
+	// Except is a regex for `grep -E` that excludes fuzz targets. It is matched against names like "github.com/foo/bar/internal/parser.FuzzParse".
+	Except string `yaml:"except"`
+	// CITime enables a job in the CI workflow that runs `make fuzz` with this value for FUZZTIME.
+	CITime string `yaml:"ciTime"`
+}
+
+// GetTime returns the set time per fuzz target or a default.
+func (f FuzzConfiguration) GetTime() string {
+	return f.Time.UnwrapOr("30s")
+}
+
+// ToolsConfiguration appears in type Configuration.
+type ToolsConfiguration struct {
+	// Addlicense is the version of addlicense. Defaults to DefaultAddlicenseVersion.
+	Addlicense Option[string] `yaml:"addlicense"`
+	// ControllerGen is the version of controller-gen. Defaults to DefaultControllerGenVersion.
+	ControllerGen Option[string] `yaml:"controllerGen"`
+	// Goimports is the version of golang.org/x/tools that goimports is installed from. Defaults to DefaultGoimportsVersion.
+	Goimports Option[string] `yaml:"goimports"`
+	// GoLicenceDetector is the version of go-licence-detector. Defaults to DefaultGoLicenceDetectorVersion.
+	GoLicenceDetector Option[string] `yaml:"goLicenceDetector"`
+	// GolangciLint is the version of golangci-lint, both for `make install-golangci-lint` and for the checks workflow. Defaults to GolangCiLintVersion.
+	GolangciLint Option[string] `yaml:"golangciLint"`
+	// SetupEnvtest is the version of setup-envtest. Defaults to DefaultSetupEnvtestVersion.
+	SetupEnvtest Option[string] `yaml:"setupEnvtest"`
+	// Shellcheck is the version of shellcheck, as used in the names of its GitHub releases. Defaults to DefaultShellcheckVersion.
+	Shellcheck Option[string] `yaml:"shellcheck"`
+	// Typos is the version of typos, as used in the names of its GitHub releases. Defaults to DefaultTyposVersion.
+	Typos Option[string] `yaml:"typos"`
+}
+
+// GetAddlicenseVersion encodes the default for the Addlicense field.
+func (t ToolsConfiguration) GetAddlicenseVersion() string {
+	return t.Addlicense.UnwrapOr(DefaultAddlicenseVersion)
+}
+
+// GetControllerGenVersion encodes the default for the ControllerGen field.
+func (t ToolsConfiguration) GetControllerGenVersion() string {
+	return t.ControllerGen.UnwrapOr(DefaultControllerGenVersion)
+}
+
+// GetGoimportsVersion encodes the default for the Goimports field.
+func (t ToolsConfiguration) GetGoimportsVersion() string {
+	return t.Goimports.UnwrapOr(DefaultGoimportsVersion)
+}
+
+// GetGoLicenceDetectorVersion encodes the default for the GoLicenceDetector field.
+func (t ToolsConfiguration) GetGoLicenceDetectorVersion() string {
+	return t.GoLicenceDetector.UnwrapOr(DefaultGoLicenceDetectorVersion)
+}
+
+// GetGolangciLintVersion encodes the default for the GolangciLint field.
+func (t ToolsConfiguration) GetGolangciLintVersion() string {
+	return t.GolangciLint.UnwrapOr(GolangCiLintVersion)
+}
+
+// GetSetupEnvtestVersion encodes the default for the SetupEnvtest field.
+func (t ToolsConfiguration) GetSetupEnvtestVersion() string {
+	return t.SetupEnvtest.UnwrapOr(DefaultSetupEnvtestVersion)
+}
+
+// GetShellcheckVersion encodes the default for the Shellcheck field.
+func (t ToolsConfiguration) GetShellcheckVersion() string {
+	return t.Shellcheck.UnwrapOr(DefaultShellcheckVersion)
+}
+
+// GetTyposVersion encodes the default for the Typos field.
+func (t ToolsConfiguration) GetTyposVersion() string {
+	return t.Typos.UnwrapOr(DefaultTyposVersion)
 }
 
 // ReuseConfiguration appears in type Configuration.
 type ReuseConfiguration struct {
-	Enabled     Option[bool]      `yaml:"enabled"`
+	// Enabled controls whether REUSE.toml is generated. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// Annotations are added as additional [[annotations]] sections to REUSE.toml.
 	Annotations []ReuseAnnotation `yaml:"annotations"`
 }
 
@@ -94,61 +251,120 @@ func (c ReuseConfiguration) IsEnabled() bool {
 // It matches the format of the `[[annotations]]` sections in the REUSE.toml format.
 // Ref: <https://reuse.software/spec-3.3/#reusetoml>
 type ReuseAnnotation struct {
-	Paths                 []string `yaml:"paths"`
-	Precedence            string   `yaml:"precedence"`
-	SPDXFileCopyrightText string   `yaml:"SPDX-FileCopyrightText"`
-	SPDXLicenseIdentifier string   `yaml:"SPDX-License-Identifier"`
+	// Paths lists the files covered by this annotation. Globs are allowed.
+	Paths []string `yaml:"paths"`
+	// Precedence is one of "closest", "aggregate" or "override".
+	Precedence string `yaml:"precedence"`
+	// SPDXFileCopyrightText is the copyright text for the covered files.
+	SPDXFileCopyrightText string `yaml:"SPDX-FileCopyrightText"`
+	// SPDXLicenseIdentifier is the SPDX license expression for the covered files.
+	SPDXLicenseIdentifier string `yaml:"SPDX-License-Identifier"`
 }
 
 // CoverageConfiguration appears in type Configuration.
 type CoverageConfiguration struct {
-	Only   string `yaml:"only"`
+	// Only is a regex for `grep -E` that selects the packages for coverage testing.
+	Only string `yaml:"only"`
+	// Except is a regex for `grep -E` that excludes packages from coverage testing.
 	Except string `yaml:"except"`
+	// Minimum is the test coverage (in percent) that each package must reach in `make check-coverage`, unless it matches a regex in PackageMinimums.
+	Minimum float64 `yaml:"minimum"`
+	// PackageMinimums maps regexes (in Go syntax) for package names to the test coverage (in percent) that matching packages must reach in `make check-coverage`.
+	PackageMinimums map[string]float64 `yaml:"packageMinimums"`
+}
+
+// HasMinimums returns whether `make check-coverage` needs to check any packages.
+func (c CoverageConfiguration) HasMinimums() bool {
+	return c.Minimum > 0 || len(c.PackageMinimums) > 0
 }
 
 // GolangConfiguration appears in type Configuration.
 type GolangConfiguration struct {
+	// AutoupdateDependencies configures which dependencies are upgraded by `go-makefile-maker --autoupdate-deps`.
 	AutoupdateDependencies struct {
-		Enabled      bool                  `yaml:"enabled"`
+		// Enabled allows --autoupdate-deps to upgrade dependencies.
+		Enabled bool `yaml:"enabled"`
+		// ModuleNameRx matches the modules that are upgraded to their latest version.
 		ModuleNameRx regexpext.PlainRegexp `yaml:"matchModule"`
 	} `yaml:"autoupdateDependencies"`
-	EnableVendoring bool              `yaml:"enableVendoring"`
-	LdFlags         map[string]string `yaml:"ldflags"`
-	SetGoModVersion bool              `yaml:"setGoModVersion"`
+	// EnableVendoring shall be set if all dependencies are vendored into the repository. Defaults to true if vendor/modules.txt exists.
+	EnableVendoring bool `yaml:"enableVendoring"`
+	// LdFlags are linker flags that are shared between the Makefile and GoReleaser.
+	LdFlags map[string]string `yaml:"ldflags"`
+	// Reproducible makes two builds of the same commit produce identical binaries: `-trimpath -buildvcs=false` is added to GO_BUILDFLAGS,
+	// and the build date is taken from $SOURCE_DATE_EPOCH or from the commit timestamp instead of from the current time.
+	Reproducible bool `yaml:"reproducible"`
+	// Platforms lists the platforms (like "linux/amd64") that `make build-all-platforms` and `make dist` cross-compile all binaries for.
+	Platforms []string `yaml:"platforms"`
+	// SetGoModVersion updates the Go version in go.mod to the version used by go-makefile-maker.
+	SetGoModVersion bool `yaml:"setGoModVersion"`
+}
+
+// ReproducibleBuildFlags are the flags that are added to GO_BUILDFLAGS when Reproducible is set.
+var ReproducibleBuildFlags = []string{"-trimpath", "-buildvcs=false"}
+
+// DefaultBuildFlags returns the default value of GO_BUILDFLAGS.
+func (g GolangConfiguration) DefaultBuildFlags() string {
+	var flags []string
+	if g.EnableVendoring {
+		flags = append(flags, "-mod vendor")
+	}
+	if g.Reproducible {
+		flags = append(flags, ReproducibleBuildFlags...)
+	}
+	return strings.Join(flags, " ")
 }
 
 // ReviveRule appears in type GolangciLintConfiguration.
 type ReviveRule struct {
-	Name      string   `yaml:"name"`
+	// Name is the name of the revive rule.
+	Name string `yaml:"name"`
+	// Arguments configure the behavior of the rule.
 	Arguments []string `yaml:"arguments"`
 }
 
 // ForbidigoRule appears in type GolangciLintConfiguration.
 type ForbidigoRule struct {
-	Pkg     string `yaml:"pkg"`
+	// Pkg is a regex matching the forbidden package.
+	Pkg string `yaml:"pkg"`
+	// Pattern is a regex matching the forbidden identifier.
 	Pattern string `yaml:"pattern"`
-	Msg     string `yaml:"msg"`
+	// Msg is shown when the rule is violated.
+	Msg string `yaml:"msg"`
 }
 
 // GolangciLintConfiguration appears in type Configuration.
 type GolangciLintConfiguration struct {
-	ReplaceAllowList []string        `yaml:"replaceAllowList"`
-	CreateConfig     bool            `yaml:"createConfig"`
-	ErrcheckExcludes []string        `yaml:"errcheckExcludes"`
-	ForbidigoRules   []ForbidigoRule `yaml:"forbidigoRules"`
-	SkipDirs         []string        `yaml:"skipDirs"`
-	Timeout          time.Duration   `yaml:"timeout"`
-	ReviveRules      []ReviveRule    `yaml:"reviveRules"`
+	// ReplaceAllowList lists modules that may appear in replace directives in go.mod.
+	ReplaceAllowList []string `yaml:"replaceAllowList"`
+	// CreateConfig controls whether .golangci.yaml is generated.
+	CreateConfig bool `yaml:"createConfig"`
+	// ErrcheckExcludes lists functions whose errors may be ignored, in the format accepted by errcheck.
+	ErrcheckExcludes []string `yaml:"errcheckExcludes"`
+	// ForbidigoRules are added to the forbidigo linter configuration.
+	ForbidigoRules []ForbidigoRule `yaml:"forbidigoRules"`
+	// SkipDirs lists directories that golangci-lint skips entirely.
+	SkipDirs []string `yaml:"skipDirs"`
+	// Timeout overrides the run.timeout option of golangci-lint, e.g. "3m".
+	Timeout time.Duration `yaml:"timeout"`
+	// ReviveRules enables the revive linter with only the given rules.
+	ReviveRules []ReviveRule `yaml:"reviveRules"`
 }
 
 // GoReleaserConfiguration appears in type Configuration.
 type GoReleaserConfiguration struct {
-	CreateConfig Option[bool]      `yaml:"createConfig"`
-	BinaryName   string            `yaml:"binaryName"`
-	Files        *[]string         `yaml:"files"`
-	Format       string            `yaml:"format"`
-	Ldflags      map[string]string `yaml:"ldflags"`
-	NameTemplate string            `yaml:"nameTemplate"`
+	// CreateConfig controls whether .goreleaser.yaml is generated. Defaults to whether the release workflow is enabled.
+	CreateConfig Option[bool] `yaml:"createConfig"`
+	// BinaryName corresponds to the builds[].binary option. If set, only the binary with this name (or else the first binary) is released.
+	BinaryName string `yaml:"binaryName"`
+	// Files lists extra files for the release archives. Defaults to CHANGELOG.md, LICENSE and README.md.
+	Files *[]string `yaml:"files"`
+	// Format corresponds to the archives[].format option.
+	Format string `yaml:"format"`
+	// Ldflags are linker flags that are only used by GoReleaser.
+	Ldflags map[string]string `yaml:"ldflags"`
+	// NameTemplate corresponds to the archives[].name_template option.
+	NameTemplate string `yaml:"nameTemplate"`
 }
 
 // ShouldCreateConfig encodes that the default state for the CreateConfig field is `false`.
@@ -156,17 +372,31 @@ func (g GoReleaserConfiguration) ShouldCreateConfig() bool {
 	return g.CreateConfig.UnwrapOr(false)
 }
 
+// ShouldRenderGoReleaserConfig returns whether a GoReleaser config file shall be rendered.
+// Unless configured explicitly, this is the case when the release workflow is enabled.
+func (c Configuration) ShouldRenderGoReleaserConfig() bool {
+	if c.GoReleaser.CreateConfig.IsNone() {
+		return c.GitHubWorkflow != nil && c.GitHubWorkflow.Release.IsEnabled(c.GoReleaser)
+	}
+	return c.GoReleaser.ShouldCreateConfig()
+}
+
 // SpellCheckConfiguration appears in type Configuration.
 type SpellCheckConfiguration struct {
+	// IgnoreWords is not supported anymore.
 	IgnoreWords []string `yaml:"ignoreWords"`
 }
 
 // TyposConfiguration appears in type Configuration.
 type TyposConfiguration struct {
-	Enabled                   Option[bool]      `yaml:"enabled"`
-	ExtendExcludes            []string          `yaml:"extendExcludes"`
-	ExtendIgnoreIdentifiersRe []string          `yaml:"extendIgnoreIdentifiersRe"`
-	ExtendWords               map[string]string `yaml:"extendWords"`
+	// Enabled controls whether typos is run. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// ExtendExcludes maps to the files.extend-excludes option of typos.
+	ExtendExcludes []string `yaml:"extendExcludes"`
+	// ExtendIgnoreIdentifiersRe maps to the default.extend-ignore-identifiers-re option of typos.
+	ExtendIgnoreIdentifiersRe []string `yaml:"extendIgnoreIdentifiersRe"`
+	// ExtendWords maps to the default.extend-words option of typos, from the wrong spelling to the correct spelling.
+	ExtendWords map[string]string `yaml:"extendWords"`
 }
 
 // IsEnabled encodes that the default state for the Enabled field is `true`.
@@ -182,32 +412,51 @@ type GithubWorkflowConfiguration struct {
 	// These global-level settings are applicable for all workflows. They are
 	// superseded by their workflow-level counterpart(s).
 	Global struct {
-		DefaultBranch string         `yaml:"defaultBranch"`
-		GoVersion     Option[string] `yaml:"goVersion"`
+		// DefaultBranch is the branch on which pushes trigger the workflows. Defaults to the HEAD of the origin remote.
+		DefaultBranch string `yaml:"defaultBranch"`
+		// GoVersion is the Go version used in all workflows.
+		GoVersion Option[string] `yaml:"goVersion"`
 	} `yaml:"global"`
 
-	CI                  CIWorkflowConfig             `yaml:"ci"`
-	IsSelfHostedRunner  bool                         `yaml:"-"`
-	IsSugarRunner       bool                         `yaml:"-"`
-	License             LicenseWorkflowConfig        `yaml:"license"`
-	PushContainerToGhcr PushContainerToGhcrConfig    `yaml:"pushContainerToGhcr"`
-	Release             ReleaseWorkflowConfig        `yaml:"release"`
-	SecurityChecks      SecurityChecksWorkflowConfig `yaml:"securityChecks"`
-	PushHelmChartToGhcr PushHelmChartToGhcrConfig    `yaml:"pushHelmChartToGhcr"`
+	// CI configures the workflow that builds, lints and tests the code.
+	CI                 CIWorkflowConfig `yaml:"ci"`
+	IsSelfHostedRunner bool             `yaml:"-"`
+	IsSugarRunner      bool             `yaml:"-"`
+	// License configures the workflow that checks license headers.
+	License LicenseWorkflowConfig `yaml:"license"`
+	// PushContainerToGhcr configures the workflow that pushes the container image to ghcr.io.
+	PushContainerToGhcr PushContainerToGhcrConfig `yaml:"pushContainerToGhcr"`
+	// Release configures the workflow that creates GitHub releases with GoReleaser.
+	Release ReleaseWorkflowConfig `yaml:"release"`
+	// SecurityChecks configures the CodeQL and dependency review workflows.
+	SecurityChecks SecurityChecksWorkflowConfig `yaml:"securityChecks"`
+	// PushHelmChartToGhcr configures the workflow that pushes a Helm chart to ghcr.io.
+	PushHelmChartToGhcr PushHelmChartToGhcrConfig `yaml:"pushHelmChartToGhcr"`
+}
+
+// GetGoVersion returns the set Go version for all workflows or a default.
+func (g GithubWorkflowConfiguration) GetGoVersion() string {
+	return g.Global.GoVersion.UnwrapOr(DefaultGoVersion)
 }
 
 // CIWorkflowConfig appears in type Configuration.
 type CIWorkflowConfig struct {
-	Enabled           bool     `yaml:"enabled"`
-	PrepareMakeTarget string   `yaml:"prepareMakeTarget"`
-	IgnorePaths       []string `yaml:"ignorePaths"`
-	RunsOn            []string `yaml:"runOn"`
+	// Enabled controls whether the CI workflow is generated.
+	Enabled bool `yaml:"enabled"`
+	// PrepareMakeTarget is a make target that is run before all checks.
+	PrepareMakeTarget string `yaml:"prepareMakeTarget"`
+	// IgnorePaths lists filename patterns that do not trigger the workflow.
+	IgnorePaths []string `yaml:"ignorePaths"`
+	// RunsOn lists the runners for the build and test jobs. Defaults to ubuntu-latest.
+	RunsOn []string `yaml:"runOn"`
 }
 
 // LicenseWorkflowConfig appears in type Configuration.
 type LicenseWorkflowConfig struct {
-	Enabled        Option[bool] `yaml:"enabled"`
-	IgnorePatterns []string     `yaml:"ignorePatterns"`
+	// Enabled controls whether the license workflow is generated. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// IgnorePatterns lists doublestar patterns of files that are not checked.
+	IgnorePatterns []string `yaml:"ignorePatterns"`
 }
 
 // IsEnabled encodes that the default state for the Enabled field is `true`.
@@ -217,29 +466,62 @@ func (l LicenseWorkflowConfig) IsEnabled() bool {
 
 // PushContainerToGhcrConfig appears in type GithubWorkflowConfiguration.
 type PushContainerToGhcrConfig struct {
-	Enabled     bool     `yaml:"enabled"`
-	Platforms   string   `yaml:"platforms"`
+	// Enabled controls whether the workflow is generated.
+	Enabled bool `yaml:"enabled"`
+	// Platforms is a comma-separated list of platforms to build the image for. Defaults to linux/amd64.
+	Platforms string `yaml:"platforms"`
+	// TagStrategy selects which tags are pushed.
 	TagStrategy []string `yaml:"tagStrategy"`
 }
 
+// TagStrategies lists the accepted values for PushContainerToGhcrConfig.TagStrategy.
+var TagStrategies = []string{"edge", "latest", "semver", "sha"}
+
 // PushHelmChartToGhcrConfig appears in type GithubWorkflowConfiguration.
 type PushHelmChartToGhcrConfig struct {
-	Path              Option[string] `yaml:"path"`
-	Lint              Option[bool]   `yaml:"lint"`
-	DependencyUpdate  Option[bool]   `yaml:"dependencyUpdate"`
-	DisableVersioning bool           `yaml:"disableVersioning"`
+	// Path is the path of the Helm chart within the repository. The workflow is only generated if this is set.
+	Path Option[string] `yaml:"path"`
+	// Lint controls whether the chart is linted before pushing. Defaults to true.
+	Lint Option[bool] `yaml:"lint"`
+	// DependencyUpdate controls whether chart dependencies are updated before pushing. Defaults to true.
+	DependencyUpdate Option[bool] `yaml:"dependencyUpdate"`
+	// DisableVersioning uses the version from Chart.yaml instead of deriving it from the container image tags.
+	DisableVersioning bool `yaml:"disableVersioning"`
+}
+
+// ShouldLint encodes that the default state for the Lint field is `true`.
+func (p PushHelmChartToGhcrConfig) ShouldLint() bool {
+	return p.Lint.UnwrapOr(true)
+}
+
+// ShouldUpdateDependencies encodes that the default state for the DependencyUpdate field is `true`.
+func (p PushHelmChartToGhcrConfig) ShouldUpdateDependencies() bool {
+	return p.DependencyUpdate.UnwrapOr(true)
 }
 
 // ReleaseWorkflowConfig appears in type ReleaseWorkflowConfig.
 type ReleaseWorkflowConfig struct {
+	// Enabled controls whether the release workflow is generated. Defaults to goReleaser.createConfig.
 	Enabled Option[bool] `yaml:"enabled"`
 	// ReleasePR opts into PR-driven release automation. Defaults to true.
 	ReleasePR Option[bool] `yaml:"releasePR"`
 }
 
+// IsEnabled encodes that the release workflow is enabled by default if a GoReleaser config is created.
+func (r ReleaseWorkflowConfig) IsEnabled(g GoReleaserConfiguration) bool {
+	return r.Enabled.UnwrapOr(g.ShouldCreateConfig())
+}
+
+// IsReleasePREnabled encodes that PR-driven releases are enabled by default if the release workflow is enabled.
+func (r ReleaseWorkflowConfig) IsReleasePREnabled(g GoReleaserConfiguration) bool {
+	return r.IsEnabled(g) && r.ReleasePR.UnwrapOr(true)
+}
+
 // SecurityChecksWorkflowConfig appears in type Configuration.
 type SecurityChecksWorkflowConfig struct {
-	Enabled Option[bool]   `yaml:"enabled"`
+	// Enabled controls whether the CodeQL and dependency review workflows are generated. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// Queries is the CodeQL query suite. Defaults to security-extended.
 	Queries Option[string] `yaml:"queries"`
 }
 
@@ -248,11 +530,19 @@ func (s SecurityChecksWorkflowConfig) IsEnabled() bool {
 	return s.Enabled.UnwrapOr(true)
 }
 
+// GetQueries returns the set CodeQL query suite or a default.
+func (s SecurityChecksWorkflowConfig) GetQueries() string {
+	return s.Queries.UnwrapOr("security-extended")
+}
+
 // ShellCheckConfiguration appears in type Configuration.
 type ShellCheckConfiguration struct {
-	Enabled     Option[bool] `yaml:"enabled"`
-	IgnorePaths []string     `yaml:"ignorePaths"`
-	Opts        string       `yaml:"opts"`
+	// Enabled controls whether ShellCheck is run. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// IgnorePaths lists path patterns of shell scripts that are not checked.
+	IgnorePaths []string `yaml:"ignorePaths"`
+	// Opts are additional options for shellcheck.
+	Opts string `yaml:"opts"`
 }
 
 // AllIgnorePaths appends the vendor paths to the IgnorePaths when vendoring is enabled.
@@ -270,73 +560,153 @@ func (s ShellCheckConfiguration) IsEnabled() bool {
 
 // PackageRule appears in type Configuration.
 type PackageRule struct {
-	MatchPackageNames           []string     `yaml:"matchPackageNames" json:"matchPackageNames,omitempty"`
-	MatchUpdateTypes            []string     `yaml:"matchUpdateTypes" json:"matchUpdateTypes,omitempty"`
-	MatchDepTypes               []string     `yaml:"matchDepTypes" json:"matchDepTypes,omitempty"`
-	MatchFileNames              []string     `yaml:"matchFileNames" json:"matchFileNames,omitempty"`
-	MatchManagers               []string     `yaml:"matchManagers" json:"matchManagers,omitempty"`
-	Extends                     []string     `yaml:"extends" json:"extends,omitempty"`
-	AllowedVersions             string       `yaml:"allowedVersions" json:"allowedVersions,omitempty"`
-	AutoMerge                   bool         `yaml:"automerge" json:"automerge,omitempty"`
+	// MatchPackageNames is documented at <https://docs.renovatebot.com/configuration-options/#packagerulesmatchpackagenames>.
+	MatchPackageNames []string `yaml:"matchPackageNames" json:"matchPackageNames,omitempty"`
+	// MatchUpdateTypes is documented at <https://docs.renovatebot.com/configuration-options/#packagerulesmatchupdatetypes>.
+	MatchUpdateTypes []string `yaml:"matchUpdateTypes" json:"matchUpdateTypes,omitempty"`
+	// MatchDepTypes is documented at <https://docs.renovatebot.com/configuration-options/#packagerulesmatchdeptypes>.
+	MatchDepTypes []string `yaml:"matchDepTypes" json:"matchDepTypes,omitempty"`
+	// MatchFileNames is documented at <https://docs.renovatebot.com/configuration-options/#packagerulesmatchfilenames>.
+	MatchFileNames []string `yaml:"matchFileNames" json:"matchFileNames,omitempty"`
+	// MatchManagers is documented at <https://docs.renovatebot.com/configuration-options/#packagerulesmatchmanagers>.
+	MatchManagers []string `yaml:"matchManagers" json:"matchManagers,omitempty"`
+	// Extends lists presets for this rule.
+	Extends []string `yaml:"extends" json:"extends,omitempty"`
+	// AllowedVersions restricts the versions that updates are proposed for.
+	AllowedVersions string `yaml:"allowedVersions" json:"allowedVersions,omitempty"`
+	// AutoMerge allows Renovate to merge PRs for this rule automatically.
+	AutoMerge bool `yaml:"automerge" json:"automerge,omitempty"`
+	// DependencyDashboardApproval requires approval in the dependency dashboard before PRs are created.
 	DependencyDashboardApproval Option[bool] `yaml:"dependencyDashboardApproval" json:"dependencyDashboardApproval,omitzero"`
-	Enabled                     Option[bool] `yaml:"enabled" json:"enabled,omitzero"`
-	GroupName                   string       `yaml:"groupName" json:"groupName,omitempty"`
-	MinimumReleaseAge           string       `yaml:"minimumReleaseAge" json:"minimumReleaseAge,omitempty"`
-	PinDigests                  Option[bool] `yaml:"pinDigests" json:"pinDigests,omitzero"`
-	SeparateMinorPatch          Option[bool] `yaml:"separateMinorPatch" json:"separateMinorPatch,omitzero"`
+	// Enabled can be set to false to disable updates for this rule.
+	Enabled Option[bool] `yaml:"enabled" json:"enabled,omitzero"`
+	// GroupName groups all updates for this rule into one PR.
+	GroupName string `yaml:"groupName" json:"groupName,omitempty"`
+	// MinimumReleaseAge delays updates until the release is at least this old, e.g. "14 days".
+	MinimumReleaseAge string `yaml:"minimumReleaseAge" json:"minimumReleaseAge,omitempty"`
+	// PinDigests pins container images and actions to digests.
+	PinDigests Option[bool] `yaml:"pinDigests" json:"pinDigests,omitzero"`
+	// SeparateMinorPatch creates separate PRs for minor and patch updates.
+	SeparateMinorPatch Option[bool] `yaml:"separateMinorPatch" json:"separateMinorPatch,omitzero"`
 }
 
 // RenovateConfig appears in type Configuration.
 type RenovateConfig struct {
-	Enabled        bool          `yaml:"enabled"`
-	Assignees      []string      `yaml:"assignees"`
-	GoVersion      string        `yaml:"goVersion"`
-	PackageRules   []PackageRule `yaml:"packageRules"`
-	CustomManagers []any         `yaml:"customManagers"`
+	// Enabled controls whether the Renovate config is generated.
+	Enabled bool `yaml:"enabled"`
+	// Assignees lists the GitHub handles of the users that Renovate PRs are assigned to.
+	Assignees []string `yaml:"assignees"`
+	// GoVersion restricts Go updates to this minor version. Defaults to the Go version from go.mod.
+	GoVersion string `yaml:"goVersion"`
+	// PackageRules are added after the default package rules.
+	PackageRules []PackageRule `yaml:"packageRules"`
+	// CustomManagers are passed through to the customManagers option of Renovate.
+	CustomManagers []any `yaml:"customManagers"`
 }
 
 // EnvRcConfig appears in type Configuration.
 type EnvRcConfig struct {
-	Enabled        Option[bool]      `yaml:"enabled"`
+	// Enabled controls whether .envrc is generated. Defaults to nix.enabled.
+	Enabled Option[bool] `yaml:"enabled"`
+	// VariableValues are exported by .envrc in addition to the top-level variables, taking precedence over them.
 	VariableValues map[string]string `yaml:"variables"`
 }
 
+// IsEnabled encodes that the .envrc file is rendered by default if Nix is enabled.
+func (e EnvRcConfig) IsEnabled(n NixConfig) bool {
+	return e.Enabled.UnwrapOr(n.IsEnabled())
+}
+
 // DockerfileConfig appears in type Configuration.
 type DockerfileConfig struct {
-	Enabled              bool         `yaml:"enabled"`
-	CheckEnv             []string     `yaml:"checkEnv"`
-	WithTestTarget       Option[bool] `yaml:"withTestTarget"`
-	CrossCompile         Option[bool] `yaml:"crossCompile"`
-	Entrypoint           []string     `yaml:"entrypoint"`
-	ExtraBuildDirectives []string     `yaml:"extraBuildDirectives"`
-	ExtraBuildPackages   []string     `yaml:"extraBuildPackages"`
-	ExtraBuildStages     []string     `yaml:"extraBuildStages"`
-	ExtraDirectives      []string     `yaml:"extraDirectives"`
-	ExtraIgnores         []string     `yaml:"extraIgnores"`
-	ExtraPackages        []string     `yaml:"extraPackages"`
-	RunAsRoot            bool         `yaml:"runAsRoot"`
-	UseBuildKit          bool         `yaml:"useBuildKit"`
-	WithLinkerdAwait     bool         `yaml:"withLinkerdAwait"`
+	// Enabled controls whether Dockerfile and .dockerignore are generated.
+	Enabled bool `yaml:"enabled"`
+	// CheckEnv lists environment variables for running `make check` during the build.
+	CheckEnv []string `yaml:"checkEnv"`
+	// WithTestTarget controls whether the Dockerfile has a test target. Defaults to true.
+	WithTestTarget Option[bool] `yaml:"withTestTarget"`
+	// CrossCompile builds natively on the build platform and cross-compiles the binaries, which disables CGO. Defaults to whether githubWorkflow.pushContainerToGhcr.platforms lists multiple platforms.
+	CrossCompile Option[bool] `yaml:"crossCompile"`
+	// Entrypoint overrides the entrypoint of the image.
+	Entrypoint []string `yaml:"entrypoint"`
+	// ExtraBuildDirectives are added to the build stage after `make install`.
+	ExtraBuildDirectives []string `yaml:"extraBuildDirectives"`
+	// ExtraBuildPackages lists extra Alpine packages for the build stage.
+	ExtraBuildPackages []string `yaml:"extraBuildPackages"`
+	// ExtraBuildStages are added at the top of the Dockerfile.
+	ExtraBuildStages []string `yaml:"extraBuildStages"`
+	// ExtraDirectives are added near the end of the Dockerfile.
+	ExtraDirectives []string `yaml:"extraDirectives"`
+	// ExtraIgnores are added to .dockerignore.
+	ExtraIgnores []string `yaml:"extraIgnores"`
+	// ExtraPackages lists extra Alpine packages for the final image.
+	ExtraPackages []string `yaml:"extraPackages"`
+	// RunAsRoot skips dropping privileges to appuser:appgroup in the final image.
+	RunAsRoot bool `yaml:"runAsRoot"`
+	// UseBuildKit uses Docker BuildKit features in the Dockerfile.
+	UseBuildKit bool `yaml:"useBuildKit"`
+	// WithLinkerdAwait prepends linkerd-await to the entrypoint.
+	WithLinkerdAwait bool `yaml:"withLinkerdAwait"`
+}
+
+// ShouldRenderTestTarget encodes that the default state for the WithTestTarget field is `true`.
+func (d DockerfileConfig) ShouldRenderTestTarget() bool {
+	return d.WithTestTarget.UnwrapOr(true)
+}
+
+// ShouldCrossCompile returns whether the Dockerfile shall cross-compile the binaries.
+// Unless configured explicitly, this is the case when the container image is pushed for multiple platforms.
+func (c Configuration) ShouldCrossCompile() bool {
+	return c.Dockerfile.CrossCompile.UnwrapOr(
+		c.GitHubWorkflow != nil && strings.Contains(c.GitHubWorkflow.PushContainerToGhcr.Platforms, ","),
+	)
 }
 
 // ControllerGen appears in type Configuration.
 type ControllerGen struct {
-	Enabled                      Option[bool] `yaml:"enabled"`
-	CrdOutputPath                string       `yaml:"crdOutputPath"`
-	ObjectHeaderFile             string       `yaml:"objectHeaderFile"`
-	RBACRoleName                 string       `yaml:"rbacRoleName"`
-	RBACOutputPath               string       `yaml:"rbacOutputPath"`
-	ApplyconfigurationHeaderFile string       `yaml:"applyconfigurationHeaderFile"`
-	AllowDangerousTypes          bool         `yaml:"allowDangerousTypes"`
+	// Enabled controls whether controller-gen is run. Defaults to whether go.mod requires sigs.k8s.io/controller-runtime.
+	Enabled Option[bool] `yaml:"enabled"`
+	// CrdOutputPath is the output directory for CRDs. Defaults to crd.
+	CrdOutputPath string `yaml:"crdOutputPath"`
+	// ObjectHeaderFile is the header file for generated object helpers.
+	ObjectHeaderFile string `yaml:"objectHeaderFile"`
+	// RBACRoleName is the name of the generated RBAC role. Defaults to the last element of the module path.
+	RBACRoleName string `yaml:"rbacRoleName"`
+	// RBACOutputPath is the output directory for RBAC manifests. Defaults to config/rbac.
+	RBACOutputPath string `yaml:"rbacOutputPath"`
+	// ApplyconfigurationHeaderFile is the header file for generated apply configurations.
+	ApplyconfigurationHeaderFile string `yaml:"applyconfigurationHeaderFile"`
+	// AllowDangerousTypes allows float32 and float64 fields in CRDs.
+	AllowDangerousTypes bool `yaml:"allowDangerousTypes"`
+}
+
+// IsEnabled encodes that controller-gen is enabled by default for Kubernetes controllers.
+func (c ControllerGen) IsEnabled(isKubernetesController bool) bool {
+	return c.Enabled.UnwrapOr(isKubernetesController)
 }
 
 // LicenseConfig appears in type Configuration.
 type LicenseConfig struct {
-	AddHeaders        Option[bool]            `yaml:"addHeaders"`
-	CheckDependencies Option[bool]            `yaml:"checkDependencies"`
+	// AddHeaders controls whether license headers are added to and checked in source files. Defaults to true for SAP projects.
+	AddHeaders Option[bool] `yaml:"addHeaders"`
+	// CheckDependencies controls whether the licenses of dependencies are checked. Defaults to true for SAP projects.
+	CheckDependencies Option[bool] `yaml:"checkDependencies"`
+	// GoLicenseDetector configures go-licence-detector.
 	GoLicenseDetector GoLicenseDetectorConfig `yaml:"goLicenseDetector"`
-	Copyright         Option[string]          `yaml:"copyright"`
-	SPDX              Option[string]          `yaml:"spdx"`
+	// Copyright is used in the copyright line of license headers.
+	Copyright Option[string] `yaml:"copyright"`
+	// SPDX is the SPDX identifier of the license used in license headers.
+	SPDX Option[string] `yaml:"spdx"`
+}
+
+// ShouldAddLicenseHeaders encodes that license headers are added by default for SAP projects.
+func (c Configuration) ShouldAddLicenseHeaders() bool {
+	return c.License.AddHeaders.UnwrapOr(c.Metadata.IsSAPProject())
+}
+
+// ShouldCheckLicenseDependencies encodes that the licenses of dependencies are checked by default for SAP projects.
+func (c Configuration) ShouldCheckLicenseDependencies() bool {
+	return c.License.CheckDependencies.UnwrapOr(c.Metadata.IsSAPProject())
 }
 
 // GetCopyright returns the set copyright string or a default.
@@ -351,26 +721,92 @@ func (l LicenseConfig) GetSPDX() string {
 
 // GoLicenseDetectorConfig appears in type LicenseConfig.
 type GoLicenseDetectorConfig struct {
+	// Overrides are added to the default overrides of go-licence-detector.
 	Overrides []LicenseDetectorOverride `yaml:"overrides"`
 }
 
 // LicenseDetectorOverride represents entries as described in
 // https://github.com/elastic/go-licence-detector#adding-overrides
 type LicenseDetectorOverride struct {
-	Name                    string `yaml:"name" json:"name,omitzero"`
-	LicenceFile             string `yaml:"licenceFile" json:"licenceFile,omitzero"`
-	LicenceType             string `yaml:"licenceType" json:"licenceType,omitzero"`
-	URL                     string `yaml:"url" json:"url,omitzero"`
+	// Name is the module path of the dependency.
+	Name string `yaml:"name" json:"name,omitzero"`
+	// LicenceFile is the path of the license file within the dependency.
+	LicenceFile string `yaml:"licenceFile" json:"licenceFile,omitzero"`
+	// LicenceType is the SPDX identifier of the license.
+	LicenceType string `yaml:"licenceType" json:"licenceType,omitzero"`
+	// URL overrides the URL of the dependency.
+	URL string `yaml:"url" json:"url,omitzero"`
+	// LicenceTextOverrideFile is the path of a file containing the license text.
 	LicenceTextOverrideFile string `yaml:"licenceTextOverrideFile" json:"licenceTextOverrideFile,omitzero"`
 }
 
 // MakefileConfig appears in type Configuration.
 type MakefileConfig struct {
-	Enabled Option[bool] `yaml:"enabled"` // this is a pointer to bool to treat an absence as true for backwards compatibility
+	// Enabled controls whether the Makefile is generated. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// Targets are custom targets that are added to the Makefile like the generated ones.
+	Targets []MakefileTarget `yaml:"targets"`
+	// Overrides change the targets in the Makefile (both generated and custom ones), keyed by target name.
+	Overrides map[string]MakefileOverride `yaml:"overrides"`
+	// LocalTools installs the tools from the install-* targets into build/tools/$NAME-$VERSION instead of into the global GOBIN,
+	// and puts these directories in front of $PATH for all recipes. `make clean` keeps them unless CLEAN_TOOLS=1 is given.
+	LocalTools bool `yaml:"localTools"`
+}
+
+// IsEnabled encodes that the default state for the Enabled field is `true`.
+func (m MakefileConfig) IsEnabled() bool {
+	return m.Enabled.UnwrapOr(true)
+}
+
+// MakefileCategories lists the categories of Makefile targets, in the order in which they appear in the Makefile.
+var MakefileCategories = []string{"general", "prepare", "build", "test", "development"}
+
+// MakefileTarget appears in type MakefileConfig.
+type MakefileTarget struct {
+	// Name is the name of the target.
+	Name string `yaml:"name"`
+	// Description is shown in `make help`. Targets without description are not listed there.
+	Description string `yaml:"description"`
+	// Category selects the section of the Makefile (and of `make help`) that the target is put in. Defaults to "general".
+	Category Option[string] `yaml:"category"`
+	// Phony marks the target as not producing a file of the same name, so that its recipe runs every time. Defaults to true.
+	Phony Option[bool] `yaml:"phony"`
+	// Prerequisites are the targets or files that need to be up to date before the recipe runs.
+	Prerequisites []string `yaml:"prerequisites"`
+	// OrderOnlyPrerequisites need to exist before the recipe runs, but do not cause the recipe to run when they change.
+	OrderOnlyPrerequisites []string `yaml:"orderOnlyPrerequisites"`
+	// Recipe contains the commands of the target, one per line (without leading tab).
+	Recipe []string `yaml:"recipe"`
+}
+
+// MakefileOverride appears in type MakefileConfig.
+// If multiple changes are given, the recipe is replaced first, and then further lines are added before and after it.
+type MakefileOverride struct {
+	// AddPrerequisites are added to the prerequisites of the target.
+	AddPrerequisites []string `yaml:"addPrerequisites"`
+	// PrependRecipe contains commands that are run before the recipe of the target.
+	PrependRecipe []string `yaml:"prependRecipe"`
+	// AppendRecipe contains commands that are run after the recipe of the target.
+	AppendRecipe []string `yaml:"appendRecipe"`
+	// ReplaceRecipe replaces the recipe of the target. If set to an empty list, the target has no recipe.
+	ReplaceRecipe Option[[]string] `yaml:"replaceRecipe"`
+	// Hide removes the target from the output of `make help`.
+	Hide bool `yaml:"hide"`
+}
+
+// GetCategory encodes that the default category is "general".
+func (t MakefileTarget) GetCategory() string {
+	return t.Category.UnwrapOr("general")
+}
+
+// IsPhony encodes that the default state for the Phony field is `true`.
+func (t MakefileTarget) IsPhony() bool {
+	return t.Phony.UnwrapOr(true)
 }
 
 // Metadata appears in type Configuration.
 type Metadata struct {
+	// URL is the URL of the repository, e.g. https://github.com/sapcc/go-makefile-maker.
 	URL string `yaml:"url"`
 }
 
@@ -388,104 +824,41 @@ func (m Metadata) IsSAPProject() bool {
 
 // NixConfig appears in type Configuration.
 type NixConfig struct {
-	Enabled        Option[bool] `yaml:"enabled"`
-	ExtraLibraries []string     `yaml:"extraLibraries"`
-	ExtraPackages  []string     `yaml:"extraPackages"`
+	// Enabled controls whether shell.nix is generated. Defaults to true.
+	Enabled Option[bool] `yaml:"enabled"`
+	// ExtraLibraries are added to buildInputs in shell.nix.
+	ExtraLibraries []string `yaml:"extraLibraries"`
+	// ExtraPackages are added to nativeBuildInputs in shell.nix.
+	ExtraPackages []string `yaml:"extraPackages"`
+}
+
+// IsEnabled encodes that the default state for the Enabled field is `true`.
+func (n NixConfig) IsEnabled() bool {
+	return n.Enabled.UnwrapOr(true)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // Helper functions
 
-// Validate checks the provided Configuration for integrity.
-func (c *Configuration) Validate() {
-	if len(c.SpellCheck.IgnoreWords) > 0 {
-		logg.Fatal("SpellCheck/misspell is deprecated, please migrate to typos")
-	}
-
-	if c.Dockerfile.Enabled {
-		if c.Metadata.URL == "" {
-			logg.Fatal("metadata.url must be set when docker.enabled is true")
+// ConfigurationPath is the path of the configuration file, relative to the repository root.
+const ConfigurationPath = "Makefile.maker.yaml"
+
+// TestReportHelperPath is the path of the helper program for the test reports (see TestConfiguration.Reports),
+// which is generated alongside the Makefile and also used by the CI workflow.
+const TestReportHelperPath = "testing/test-report.go"
+
+// DetectEnvironment fills in the settings that are not read from the configuration file,
+// but derived from the metadata and from the contents of the repository.
+func (c *Configuration) DetectEnvironment() {
+	// The github.com/ prefix is just a safeguard to avoid false positives when the metadata.url is not complete.
+	if c.GitHubWorkflow != nil && !strings.Contains(c.Metadata.URL, "github.com/") {
+		c.GitHubWorkflow.IsSelfHostedRunner = true
+		if strings.Contains(c.Metadata.URL, "/sap-cloud-infrastructure/") {
+			c.GitHubWorkflow.IsSugarRunner = true
 		}
 	}
 
-	// because of the special logic for `installTo: /opt/resource`, only one binary is allowed to install there
-	optResourceBinaryCount := 0
-	for _, bin := range c.Binaries {
-		if filepath.Clean(bin.InstallTo) == "/opt/resource" {
-			optResourceBinaryCount++
-		}
-	}
-	if optResourceBinaryCount > 1 {
-		logg.Fatal("cannot have more than one entry in 'binaries' with `installTo: /opt/resource`")
-	}
-
-	// Validate GolangciLintConfiguration.
-	if (len(c.GolangciLint.ErrcheckExcludes) > 0 || len(c.GolangciLint.ForbidigoRules) > 0 || len(c.GolangciLint.ReplaceAllowList) > 0) && !c.GolangciLint.CreateConfig {
-		logg.Fatal("golangciLint.createConfig must be set to 'true' if golangciLint.errcheckExcludes, golangciLint.forbidigoRules or golangciLint.replaceAllowList is defined")
-	}
-
-	for _, forbigoRule := range c.GolangciLint.ForbidigoRules {
-		if forbigoRule.Pkg == "" && forbigoRule.Pattern == "" {
-			logg.Fatal("golangciLint.forbidigoRules must have at least pkg or pattern for each rule defined")
-		}
-	}
-
-	// Validate GithubWorkflowConfiguration.
-	ghwCfg := c.GitHubWorkflow
-	if ghwCfg != nil {
-		if c.Metadata.URL == "" {
-			logg.Fatal("metadata.url must be set when any github workflow is configured otherwise it cannot be determined which github runner type should be used")
-		}
-
-		// Validate global options.
-		if ghwCfg.Global.DefaultBranch == "" {
-			errMsg := "could not find default branch using git, you can define it manually by setting 'githubWorkflow.global.defaultBranch' in config"
-			cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
-			cmd.Stderr = os.Stderr
-			b, err := cmd.Output()
-			if err != nil {
-				logg.Fatal("%s: %s", errMsg, err.Error())
-			}
-
-			branch := strings.TrimPrefix(string(b), "refs/remotes/origin/")
-			if branch == string(b) {
-				logg.Fatal(errMsg)
-			} else {
-				c.GitHubWorkflow.Global.DefaultBranch = strings.TrimSpace(branch)
-			}
-		}
-
-		// Validate CI workflow configuration.
-		if ghwCfg.CI.Enabled {
-			if len(ghwCfg.CI.RunsOn) > 1 && !strings.HasPrefix(ghwCfg.CI.RunsOn[0], "ubuntu") {
-				logg.Fatal("githubWorkflow.ci.runOn must only define a single Ubuntu based runner when githubWorkflow.ci.enabled is true")
-			}
-		}
-
-		// Validate Release workflow configuration. Only flag explicit `releasePR: true`
-		// without a release workflow being rendered; the default-on case is silently
-		// inert when the release workflow itself is disabled.
-		if ghwCfg.Release.ReleasePR.UnwrapOr(false) && !ghwCfg.Release.Enabled.UnwrapOr(c.GoReleaser.ShouldCreateConfig()) {
-			logg.Fatal("githubWorkflow.release.releasePR requires githubWorkflow.release.enabled (or goReleaser.createConfig) to be true")
-		}
-	}
-
-	// for SAP projects, we require the use of:
-	// - Renovate as a Software Composition Analysis tool
-	// - GitHub Advanced Security (specifically CodeQL) as a Security Check tool
-	// in order to satisfy compliance requirements
-	if c.Metadata.IsSAPProject() {
-		if ghwCfg != nil && !ghwCfg.SecurityChecks.IsEnabled() {
-			logg.Fatal("githubWorkflow.securityChecks.enabled may not be set to false (CodeQL is required for SAP projects to satisfy compliance requirements)")
-		}
-		if !c.Renovate.Enabled {
-			logg.Fatal("renovate.enabled must be set to true (Renovate is required for SAP projects to satisfy compliance requirements)")
-		}
-	}
-
-	for _, key := range slices.Sorted(maps.Keys(c.VariableValues)) {
-		if slices.Contains([]string{"BININFO_VERSION", "BININFO_COMMIT_HASH", "BININFO_BUILD_DATE"}, key) {
-			logg.Fatal("variables cannot contain %s as it is reserved for build information", key)
-		}
+	if fs, err := os.Stat("vendor/modules.txt"); err == nil && fs != nil {
+		c.Golang.EnableVendoring = true
 	}
 }
@@ -9,8 +9,24 @@ const (
 	DefaultAlpineImage         = "3.24"
 	DefaultGoVersion           = "1.26.7"
 	DefaultPostgresVersion     = "18"
+	DefaultRedisVersion        = "8"
 	DefaultLinkerdAwaitVersion = "0.3.3"
 	DefaultGitHubComRunsOn     = "ubuntu-latest"
+
+	// DockerHubMirror is prepended to the names of images from Docker Hub on SAP-internal GitHub.
+	DockerHubMirror = "keppel.eu-de-1.cloud.sap/ccloud-dockerhub-mirror/library/"
+)
+
+// Default versions of the tools that are installed by the install-* targets in the Makefile (see ToolsConfiguration).
+// The default version of golangci-lint is GolangCiLintVersion.
+const (
+	DefaultAddlicenseVersion        = "v1.2.0"
+	DefaultControllerGenVersion     = "v0.19.0"
+	DefaultGoimportsVersion         = "v0.38.0"
+	DefaultGoLicenceDetectorVersion = "v0.7.0"
+	DefaultSetupEnvtestVersion      = "release-0.22"
+	DefaultShellcheckVersion        = "v0.11.0"
+	DefaultTyposVersion             = "v1.38.1"
 )
 
 // DefaultGitHubEnterpriseRunsOn is a map of group names to runner labels for GitHub Enterprise.
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// AppendedLists contains the paths of all list fields whose entries are appended to those from base configs
+// (see Configuration.Extends). All other lists in a config replace the respective lists from its base configs.
+var AppendedLists = []string{
+	"dockerfile.extraBuildPackages",
+	"dockerfile.extraIgnores",
+	"dockerfile.extraPackages",
+	"githubWorkflow.ci.ignorePaths",
+	"githubWorkflow.license.ignorePatterns",
+	"golangciLint.errcheckExcludes",
+	"golangciLint.forbidigoRules",
+	"golangciLint.replaceAllowList",
+	"golangciLint.reviveRules",
+	"golangciLint.skipDirs",
+	"license.goLicenseDetector.overrides",
+	"makefile.targets",
+	"nix.extraLibraries",
+	"nix.extraPackages",
+	"renovate.assignees",
+	"renovate.customManagers",
+	"renovate.packageRules",
+	"reuse.annotations",
+	"shellCheck.ignorePaths",
+	"typos.extendExcludes",
+	"typos.extendIgnoreIdentifiersRe",
+}
+
+// Document is the YAML document of the configuration file, with all base configs from `extends` merged into it.
+type Document struct {
+	// Root is the top-level mapping node, or nil if the configuration file is empty.
+	Root *yaml.Node
+	// files maps nodes that were read from base configs to the paths of those files.
+	files map[*yaml.Node]string
+}
+
+// FileOf returns the path of the file that the given node was read from.
+func (d Document) FileOf(node *yaml.Node) string {
+	if path, exists := d.files[node]; exists {
+		return path
+	}
+	return ConfigurationPath
+}
+
+// HasBaseConfigs returns whether any base configs were merged into this document.
+func (d Document) HasBaseConfigs() bool {
+	return len(d.files) > 0
+}
+
+// ErrorAt returns a ValidationError for the field at the given path (like "binaries[0].fromPackage").
+// If the field is not set in the document, the position of its closest parent is used instead.
+func (d Document) ErrorAt(path, msg string) ValidationError {
+	return d.errorAt(findNode(d.Root, path), msg)
+}
+
+// errorAt returns a ValidationError for the given node. If the node is nil, the error does not have a position.
+func (d Document) errorAt(node *yaml.Node, msg string) ValidationError {
+	if node == nil {
+		return ValidationError{File: ConfigurationPath, Message: msg}
+	}
+	return ValidationError{d.FileOf(node), node.Line, node.Column, msg}
+}
+
+// documentLoader reads config files and merges them according to their `extends` lists.
+type documentLoader struct {
+	files map[*yaml.Node]string
+	// stack contains the paths of the files that are currently being loaded, for detecting cycles
+	stack []string
+}
+
+// load parses the contents of the given config file and merges the base configs listed in its `extends` key into it.
+func (l *documentLoader) load(path string, buf []byte) (*yaml.Node, []ValidationError) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(buf, &doc)
+	if err != nil {
+		verr := parseYAMLError(Document{}, nil, err.Error())
+		verr.File = path
+		return nil, []ValidationError{verr}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if path != ConfigurationPath {
+		l.files[root] = path
+		visitNodes(root, "", func(_ string, key, value *yaml.Node) {
+			if key != nil {
+				l.files[key] = path
+			}
+			l.files[value] = path
+		})
+	}
+
+	var extends *yaml.Node
+	if root.Kind == yaml.MappingNode {
+		for idx := 0; idx+1 < len(root.Content); idx += 2 {
+			if root.Content[idx].Value == "extends" {
+				extends = root.Content[idx+1]
+			}
+		}
+	}
+	if extends == nil || extends.Kind != yaml.SequenceNode {
+		// if `extends` has the wrong type, this will be reported when decoding the merged document
+		return root, nil
+	}
+
+	l.stack = append(l.stack, filepath.Clean(path))
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	var (
+		merged *yaml.Node
+		errs   []ValidationError
+	)
+	for _, item := range extends.Content {
+		errorAt := func(msg string, args ...any) ValidationError {
+			return ValidationError{path, item.Line, item.Column, fmt.Sprintf(msg, args...)}
+		}
+		if strings.Contains(item.Value, "://") {
+			errs = append(errs, errorAt("cannot extend %s: only local files are supported as base configs", item.Value))
+			continue
+		}
+
+		basePath := filepath.Join(filepath.Dir(path), item.Value)
+		if slices.Contains(l.stack, filepath.Clean(basePath)) {
+			errs = append(errs, errorAt("cannot extend %s: the file extends itself (via %s)", basePath, strings.Join(l.stack, " -> ")))
+			continue
+		}
+		baseBuf, err := os.ReadFile(basePath)
+		if err != nil {
+			errs = append(errs, errorAt("cannot extend %s: %s", basePath, err.Error()))
+			continue
+		}
+		base, baseErrs := l.load(basePath, baseBuf)
+		errs = append(errs, baseErrs...)
+		merged = l.merge(merged, base, "")
+	}
+	return l.merge(merged, root, ""), errs
+}
+
+// merge merges the child node into the base node:
+// Mappings are merged recursively, lists from AppendedLists are concatenated, and all other values are replaced by those in the child.
+func (l *documentLoader) merge(base, child *yaml.Node, path string) *yaml.Node {
+	switch {
+	case base == nil:
+		return child
+	case child == nil:
+		return base
+	case base.Kind == yaml.MappingNode && child.Kind == yaml.MappingNode:
+		result := *child
+		result.Content = nil
+		if file, exists := l.files[child]; exists {
+			l.files[&result] = file
+		}
+
+		childValues := make(map[string]*yaml.Node)
+		for idx := 0; idx+1 < len(child.Content); idx += 2 {
+			childValues[child.Content[idx].Value] = child.Content[idx+1]
+		}
+		// keep the order of keys from the base, followed by the keys that only exist in the child
+		for idx := 0; idx+1 < len(base.Content); idx += 2 {
+			key, value := base.Content[idx], base.Content[idx+1]
+			if _, exists := childValues[key.Value]; !exists {
+				result.Content = append(result.Content, key, value)
+			}
+		}
+		for idx := 0; idx+1 < len(child.Content); idx += 2 {
+			key, value := child.Content[idx], child.Content[idx+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			for baseIdx := 0; baseIdx+1 < len(base.Content); baseIdx += 2 {
+				if base.Content[baseIdx].Value == key.Value {
+					value = l.merge(base.Content[baseIdx+1], value, childPath)
+				}
+			}
+			result.Content = append(result.Content, key, value)
+		}
+		return &result
+	case base.Kind == yaml.SequenceNode && child.Kind == yaml.SequenceNode && slices.Contains(AppendedLists, path):
+		result := *child
+		result.Content = slices.Concat(base.Content, child.Content)
+		if file, exists := l.files[child]; exists {
+			l.files[&result] = file
+		}
+		return &result
+	default:
+		return child
+	}
+}
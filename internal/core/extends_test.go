@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for path, contents := range files {
+		err := os.MkdirAll(filepath.Dir(path), 0o777)
+		if err == nil {
+			err = os.WriteFile(path, []byte(contents), 0o666)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExtends(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFiles(t, map[string]string{
+		"../org/base.yaml": `extends: [ common.yaml ]
+renovate:
+  enabled: true
+  assignees: [ alice ]
+  goVersion: "1.25"
+golangciLint:
+  skipDirs: [ foo ]
+variables:
+  FOO: base
+`,
+		"../org/common.yaml": `nix:
+  extraPackages: [ jq ]
+typos:
+  extendExcludes: [ common ]
+`,
+	})
+
+	cfg, doc, errs := ParseConfiguration([]byte(`extends: [ ../org/base.yaml ]
+renovate:
+  assignees: [ bob ]
+  goVersion: "1.26"
+golangciLint:
+  skipDirs: [ bar ]
+variables:
+  BAR: child
+typos:
+  extendExcludes: [ child ]
+`))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	// scalars are replaced, maps are merged
+	if !cfg.Renovate.Enabled || cfg.Renovate.GoVersion != "1.26" {
+		t.Errorf("expected renovate.enabled = true and renovate.goVersion = 1.26, but got %#v", cfg.Renovate)
+	}
+	if cfg.VariableValues["FOO"] != "base" || cfg.VariableValues["BAR"] != "child" {
+		t.Errorf("expected variables from both files, but got %v", cfg.VariableValues)
+	}
+	// lists are appended or replaced depending on AppendedLists
+	expectedLists := map[string][2][]string{
+		"renovate.assignees":    {cfg.Renovate.Assignees, {"alice", "bob"}},
+		"golangciLint.skipDirs": {cfg.GolangciLint.SkipDirs, {"foo", "bar"}},
+		"nix.extraPackages":     {cfg.Nix.ExtraPackages, {"jq"}},
+		"typos.extendExcludes":  {cfg.Typos.ExtendExcludes, {"common", "child"}},
+		"extends":               {cfg.Extends, {"../org/base.yaml"}},
+	}
+	for path, lists := range expectedLists {
+		if !slices.Equal(lists[0], lists[1]) {
+			t.Errorf("expected %s to be %v, but got %v", path, lists[1], lists[0])
+		}
+	}
+
+	// each value knows which file it comes from
+	expectedFiles := map[string]string{
+		"renovate.enabled":        filepath.Join("..", "org", "base.yaml"),
+		"renovate.goVersion":      ConfigurationPath,
+		"renovate.assignees[0]":   filepath.Join("..", "org", "base.yaml"),
+		"renovate.assignees[1]":   ConfigurationPath,
+		"nix.extraPackages[0]":    filepath.Join("..", "org", "common.yaml"),
+		"typos.extendExcludes[1]": ConfigurationPath,
+	}
+	for path, expected := range expectedFiles {
+		node := findNode(doc.Root, path)
+		if actual := doc.FileOf(node); actual != expected {
+			t.Errorf("expected %s to come from %s, but got %s", path, expected, actual)
+		}
+	}
+}
+
+func TestExtendsErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeFiles(t, map[string]string{
+		"a.yaml": "extends: [ b.yaml ]\n",
+		"b.yaml": "extends: [ a.yaml ]\n",
+		"c.yaml": "renovate:\n  enabled: true\n  unknownField: 42\n",
+	})
+
+	_, _, errs := ParseConfiguration([]byte("extends:\n  - a.yaml\n  - missing.yaml\n  - https://example.com/base.yaml\n"))
+	assertErrors(t, errs,
+		"Makefile.maker.yaml:3:5: cannot extend missing.yaml: open missing.yaml: no such file or directory",
+		"Makefile.maker.yaml:4:5: cannot extend https://example.com/base.yaml: only local files are supported as base configs",
+		"b.yaml:1:12: cannot extend a.yaml: the file extends itself (via Makefile.maker.yaml -> a.yaml -> b.yaml)",
+	)
+
+	// problems in base configs are reported with the position in the base config
+	_, _, errs = ParseConfiguration([]byte("extends: [ c.yaml ]\nmetadata:\n  url: https://example.com/foo\n"))
+	assertErrors(t, errs, "c.yaml:3:3: unknown field renovate.unknownField")
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// TestService describes a service that tests can depend on, like a database.
+type TestService struct {
+	// Image is the container image that runs the service in the integration job of the CI workflow.
+	Image string
+	// Port is the port where the service accepts connections.
+	Port int
+	// Env contains environment variables for the service container.
+	Env map[string]string
+	// HealthCheck is a command that succeeds once the service container is ready to accept connections.
+	HealthCheck string
+	// TestEnv contains environment variables that point the tests to the service.
+	// In the values, "${HOST}" is replaced by the hostname of the service.
+	TestEnv map[string]string
+	// NixPackage provides the service in the generated shell.nix.
+	NixPackage string
+	// AlpinePackage provides the service in the test stage of the generated Dockerfile.
+	AlpinePackage string
+	// UbuntuSetup contains the commands that install the service on github.com runners.
+	UbuntuSetup []string
+	// Modules contains Go modules whose tests start their own instance of the service.
+	// If go.mod requires any of these, the service is also installed for the unit tests.
+	Modules []string
+}
+
+// TestServices contains the services that can be listed in testPackages.integration.services.
+var TestServices = map[string]TestService{
+	"postgres": {
+		Image:       "postgres:" + DefaultPostgresVersion + "-alpine",
+		Port:        5432,
+		Env:         map[string]string{"POSTGRES_PASSWORD": "postgres"},
+		HealthCheck: "pg_isready -U postgres",
+		TestEnv: map[string]string{
+			"PGHOST":     "${HOST}",
+			"PGPORT":     "5432",
+			"PGUSER":     "postgres",
+			"PGPASSWORD": "postgres",
+			"PGSSLMODE":  "disable",
+		},
+		NixPackage:    "postgresql_" + DefaultPostgresVersion,
+		AlpinePackage: "postgresql",
+		UbuntuSetup: []string{
+			"sudo /usr/share/postgresql-common/pgdg/apt.postgresql.org.sh -y",
+			"sudo apt-get install -y --no-install-recommends postgresql-" + DefaultPostgresVersion,
+			fmt.Sprintf("export PATH=/usr/lib/postgresql/%s/bin:$PATH", DefaultPostgresVersion),
+		},
+		// `pgruntime.WithTestDB()` from go.xyrillian.de/gg/pgruntime starts its own PostgreSQL server, so the tests need the PostgreSQL binaries
+		Modules: []string{"github.com/lib/pq", "github.com/jackc/pgx/v5"},
+	},
+	"redis": {
+		Image:       "redis:" + DefaultRedisVersion + "-alpine",
+		Port:        6379,
+		HealthCheck: "redis-cli ping",
+		TestEnv: map[string]string{
+			"REDIS_HOST": "${HOST}",
+			"REDIS_PORT": "6379",
+		},
+		NixPackage:    "redis",
+		AlpinePackage: "redis",
+		UbuntuSetup: []string{
+			"sudo apt-get install -y --no-install-recommends redis-server",
+		},
+	},
+}
+
+// TestEnvFor returns the environment variables that point the tests to the service when it is reachable at the given hostname.
+func (s TestService) TestEnvFor(host string) map[string]string {
+	result := maps.Clone(s.TestEnv)
+	for key, value := range result {
+		result[key] = strings.ReplaceAll(value, "${HOST}", host)
+	}
+	return result
+}
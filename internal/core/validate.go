@@ -0,0 +1,504 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	. "go.xyrillian.de/gg/option"
+	"go.yaml.in/yaml/v3"
+)
+
+// ValidationError is a problem found in the configuration file (or in one of its base configs).
+// Line and Column are 0 if the problem cannot be attributed to a specific position.
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the builtin/error interface.
+func (e ValidationError) Error() string {
+	switch {
+	case e.Line == 0:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+}
+
+// ReadConfiguration reads and validates the configuration file.
+// The parsed YAML document is returned as well, so that callers can tell which fields were set explicitly.
+// If the configuration file has any problems, all of them are reported and the program exits.
+func ReadConfiguration() (Configuration, Document) {
+	logg.Debug("reading %s", ConfigurationPath)
+	buf := must.Return(os.ReadFile(ConfigurationPath))
+
+	cfg, doc, errs := ParseConfiguration(buf)
+	FatalOnErrors(errs)
+	return cfg, doc
+}
+
+// FatalOnErrors reports all given problems with the configuration and exits if there are any.
+func FatalOnErrors(errs []ValidationError) {
+	if len(errs) == 0 {
+		return
+	}
+	for _, err := range errs {
+		logg.Error(err.Error())
+	}
+	logg.Fatal("found %d problem(s) in %s", len(errs), ConfigurationPath)
+}
+
+var yamlErrorRx = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+var unknownFieldRx = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// ParseConfiguration decodes and validates the contents of a configuration file.
+// Base configs listed in its `extends` key are read and merged into it.
+// Instead of stopping at the first problem, all problems are collected and returned in the order of their position.
+func ParseConfiguration(buf []byte) (Configuration, Document, []ValidationError) {
+	var cfg Configuration
+	l := documentLoader{files: make(map[*yaml.Node]string)}
+	root, errs := l.load(ConfigurationPath, buf)
+	doc := Document{root, l.files}
+	if len(errs) > 0 {
+		// the merged document is incomplete, so validating it would only produce follow-up errors
+		return cfg, doc, sortErrors(errs)
+	}
+	if root == nil {
+		return cfg, doc, []ValidationError{doc.errorAt(nil, "file is empty")}
+	}
+
+	// The YAML library only reports problems with their line number, so the error messages need to be matched to the document.
+	// When base configs were merged in, the merged document has to be serialized for decoding, and the line numbers refer to that serialization.
+	lookup := root
+	if doc.HasBaseConfigs() {
+		buf = must.Return(yaml.Marshal(root))
+		var serialized yaml.Node
+		must.Succeed(yaml.Unmarshal(buf, &serialized))
+		lookup = &serialized
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	err := dec.Decode(&cfg)
+	var typeErr *yaml.TypeError
+	switch {
+	case err == nil:
+		// ok
+	case errors.As(err, &typeErr):
+		// unknown fields and values of the wrong type are reported, but the rest of the document is decoded anyway
+		for _, msg := range typeErr.Errors {
+			errs = append(errs, parseYAMLError(doc, lookup, msg))
+		}
+	default:
+		return cfg, doc, []ValidationError{parseYAMLError(doc, lookup, err.Error())}
+	}
+
+	errs = append(errs, cfg.Validate(doc)...)
+	return cfg, doc, sortErrors(errs)
+}
+
+func sortErrors(errs []ValidationError) []ValidationError {
+	// errors in the configuration file come first, followed by those in base configs
+	slices.SortStableFunc(errs, func(lhs, rhs ValidationError) int {
+		return cmp.Or(
+			cmp.Compare(boolToInt(lhs.File != ConfigurationPath), boolToInt(rhs.File != ConfigurationPath)),
+			cmp.Compare(lhs.File, rhs.File),
+			cmp.Compare(lhs.Line, rhs.Line),
+			cmp.Compare(lhs.Column, rhs.Column),
+		)
+	})
+	return errs
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseYAMLError converts an error message from the YAML library (which only knows about line numbers) into a ValidationError.
+// The line numbers refer to the lookup node, which is either the root of the document or a serialization of it.
+func parseYAMLError(doc Document, lookup *yaml.Node, msg string) ValidationError {
+	match := yamlErrorRx.FindStringSubmatch(msg)
+	if match == nil {
+		return doc.errorAt(nil, strings.TrimPrefix(msg, "yaml: "))
+	}
+	line := must.Return(strconv.Atoi(match[1]))
+	msg = match[2]
+
+	if match := unknownFieldRx.FindStringSubmatch(msg); match != nil {
+		path, node := findNodeOnLine(lookup, line, match[1])
+		if node != nil {
+			return doc.errorAt(findNode(doc.Root, path), "unknown field "+path)
+		}
+	}
+	if strings.HasPrefix(msg, "cannot unmarshal ") {
+		path, node := findNodeOnLine(lookup, line, "")
+		if node != nil {
+			return doc.errorAt(findNode(doc.Root, path), fmt.Sprintf("invalid value for %s: %s", path, msg))
+		}
+	}
+	if lookup != doc.Root {
+		// the line number refers to the serialized document, so it would only be confusing
+		return doc.errorAt(nil, msg)
+	}
+	return ValidationError{ConfigurationPath, line, 0, msg}
+}
+
+// findNodeOnLine returns the node for the first scalar value on the given line, or the first key node with the given name on that line if a name is given.
+// For scalar values in mappings, the node of their key is returned instead.
+func findNodeOnLine(doc *yaml.Node, line int, keyName string) (path string, result *yaml.Node) {
+	visitNodes(doc, "", func(p string, key, value *yaml.Node) {
+		switch {
+		case result != nil:
+			return
+		case keyName != "":
+			if key != nil && key.Line == line && key.Value == keyName {
+				path, result = p, key
+			}
+		case value.Line == line && value.Kind == yaml.ScalarNode:
+			path, result = p, value
+			if key != nil {
+				result = key
+			}
+		}
+	})
+	return path, result
+}
+
+// findNode returns the node with the given path (like "binaries[0].fromPackage").
+// For mapping entries, this is the node of the key.
+// If there is no such node, the node of its closest existing parent is returned instead, or nil if there is none.
+func findNode(root *yaml.Node, path string) (result *yaml.Node) {
+	bestMatch := ""
+	visitNodes(root, "", func(p string, key, value *yaml.Node) {
+		isParent := strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[")
+		if (p == path || isParent) && len(p) > len(bestMatch) {
+			bestMatch = p
+			result = value
+			if key != nil {
+				result = key
+			}
+		}
+	})
+	return result
+}
+
+// visitNodes calls the action for every mapping entry and every sequence item below the given node.
+// For sequence items, the key node is nil.
+func visitNodes(node *yaml.Node, path string, action func(path string, key, value *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			visitNodes(child, path, action)
+		}
+	case yaml.MappingNode:
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			key, value := node.Content[idx], node.Content[idx+1]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			action(childPath, key, value)
+			visitNodes(value, childPath, action)
+		}
+	case yaml.SequenceNode:
+		for idx, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, idx)
+			action(childPath, nil, item)
+			visitNodes(item, childPath, action)
+		}
+	}
+}
+
+type validator struct {
+	doc  Document
+	errs []ValidationError
+}
+
+// addError records a problem with the field at the given path (like "binaries[0].fromPackage").
+func (v *validator) addError(path, msg string, args ...any) {
+	v.errs = append(v.errs, v.doc.ErrorAt(path, fmt.Sprintf(msg, args...)))
+}
+
+// buildTagRx matches the build tags that can be given in `binaries[].buildTags`.
+var buildTagRx = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// envNameRx matches the variable names that can be given in `binaries[].env`.
+var envNameRx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// platformRx matches the GOOS/GOARCH pairs that can be given in `golang.platforms`.
+var platformRx = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+$`)
+
+// isValidFuzzTime returns whether the given value is accepted by `go test -fuzztime`.
+func isValidFuzzTime(value string) bool {
+	if count, ok := strings.CutSuffix(value, "x"); ok {
+		_, err := strconv.ParseUint(count, 10, 64)
+		return err == nil
+	}
+	_, err := time.ParseDuration(value)
+	return err == nil
+}
+
+// toolVersionRx matches the versions that can be given in the `tools` section.
+// Since these versions are put into shell commands, they must not contain any characters with special meaning for the shell.
+var toolVersionRx = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// DetectDefaultBranch returns the branch that the HEAD of the origin remote points to.
+// This is the default for githubWorkflow.global.defaultBranch.
+func DetectDefaultBranch() (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
+	cmd.Stderr = os.Stderr
+	b, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(b)), "refs/remotes/origin/")
+	if !ok {
+		return "", fmt.Errorf("unexpected output from git symbolic-ref: %q", strings.TrimSpace(string(b)))
+	}
+	return branch, nil
+}
+
+// Validate checks the provided Configuration for integrity and returns all problems that were found.
+// The YAML document that the Configuration was decoded from is used to find the position of each problem.
+func (c *Configuration) Validate(doc Document) []ValidationError {
+	v := validator{doc: doc}
+
+	if len(c.SpellCheck.IgnoreWords) > 0 {
+		v.addError("spellCheck.ignoreWords", "SpellCheck/misspell is deprecated, please migrate to typos")
+	}
+
+	if c.Dockerfile.Enabled {
+		if c.Metadata.URL == "" {
+			v.addError("dockerfile.enabled", "metadata.url must be set when dockerfile.enabled is true")
+		}
+	}
+
+	// because of the special logic for `installTo: /opt/resource`, only one binary is allowed to install there
+	hasOptResourceBinary := false
+	for idx, bin := range c.Binaries {
+		if !strings.HasPrefix(bin.FromPackage, ".") {
+			v.addError(fmt.Sprintf("binaries[%d].fromPackage", idx), "binaries[].fromPackage must begin with a dot, %q is not allowed!", bin.FromPackage)
+		}
+		if filepath.Clean(bin.InstallTo) == "/opt/resource" {
+			if hasOptResourceBinary {
+				v.addError(fmt.Sprintf("binaries[%d].installTo", idx), "cannot have more than one entry in 'binaries' with `installTo: /opt/resource`")
+			}
+			hasOptResourceBinary = true
+		}
+		for tagIdx, tag := range bin.BuildTags {
+			if !buildTagRx.MatchString(tag) {
+				v.addError(fmt.Sprintf("binaries[%d].buildTags[%d]", idx, tagIdx), "binaries[].buildTags must only contain letters, digits, underscores and dots, %q is not allowed", tag)
+			}
+		}
+		for flagIdx, flag := range bin.LdFlags {
+			if strings.Contains(flag, "'") {
+				v.addError(fmt.Sprintf("binaries[%d].ldflags[%d]", idx, flagIdx), "binaries[].ldflags must not contain single quotes, %q is not allowed", flag)
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(bin.Env)) {
+			switch {
+			case key == "CGO_ENABLED":
+				v.addError(fmt.Sprintf("binaries[%d].env.%s", idx, key), "binaries[].env must not contain CGO_ENABLED, use binaries[].cgo instead")
+			case !envNameRx.MatchString(key):
+				v.addError(fmt.Sprintf("binaries[%d].env.%s", idx, key), "binaries[].env must only contain valid variable names, %q is not allowed", key)
+			}
+		}
+	}
+
+	for path, fuzzTime := range map[string]string{
+		"testPackages.fuzz.time":   c.Test.Fuzz.Time.UnwrapOr(""),
+		"testPackages.fuzz.ciTime": c.Test.Fuzz.CITime,
+	} {
+		if fuzzTime != "" && !isValidFuzzTime(fuzzTime) {
+			v.addError(path, `%s must be a duration like "30s" or a number of iterations like "1000x", %q is not allowed`, path, fuzzTime)
+		}
+	}
+
+	for idx, format := range c.Test.Reports {
+		if !slices.Contains(TestReportFormats, format) {
+			v.addError(fmt.Sprintf("testPackages.reports[%d]", idx), "testPackages.reports must only contain %s, %q is not allowed", strings.Join(TestReportFormats, " or "), format)
+		}
+	}
+
+	for idx, tag := range c.Test.Integration.GetTags() {
+		if !buildTagRx.MatchString(tag) {
+			v.addError(fmt.Sprintf("testPackages.integration.tags[%d]", idx), "testPackages.integration.tags must only contain letters, digits, underscores and dots, %q is not allowed", tag)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Test.Integration.Env)) {
+		switch {
+		case !envNameRx.MatchString(key):
+			v.addError("testPackages.integration.env."+key, "testPackages.integration.env must only contain valid variable names, %q is not allowed", key)
+		case strings.Contains(c.Test.Integration.Env[key], "'"):
+			v.addError("testPackages.integration.env."+key, "testPackages.integration.env must not contain single quotes, %q is not allowed", c.Test.Integration.Env[key])
+		}
+	}
+	for idx, service := range c.Test.Integration.Services {
+		if _, exists := TestServices[service]; !exists {
+			v.addError(fmt.Sprintf("testPackages.integration.services[%d]", idx), "unknown service in testPackages.integration.services: %s (must be one of: %s)", service, strings.Join(slices.Sorted(maps.Keys(TestServices)), ", "))
+		}
+	}
+
+	if c.Coverage.Minimum < 0 || c.Coverage.Minimum > 100 {
+		v.addError("coverageTest.minimum", "coverageTest.minimum must be a percentage between 0 and 100, %g is not allowed", c.Coverage.Minimum)
+	}
+	for _, rx := range slices.Sorted(maps.Keys(c.Coverage.PackageMinimums)) {
+		path := "coverageTest.packageMinimums." + rx
+		if _, err := regexp.Compile(rx); err != nil {
+			v.addError(path, "coverageTest.packageMinimums must have valid regexes as keys, %q is not allowed: %s", rx, err.Error())
+		} else if strings.Contains(rx, "'") {
+			v.addError(path, "coverageTest.packageMinimums must not have single quotes in its keys, %q is not allowed", rx)
+		}
+		if minimum := c.Coverage.PackageMinimums[rx]; minimum < 0 || minimum > 100 {
+			v.addError(path, "coverageTest.packageMinimums must have percentages between 0 and 100 as values, %g is not allowed", minimum)
+		}
+	}
+
+	for idx, platform := range c.Golang.Platforms {
+		if !platformRx.MatchString(platform) {
+			v.addError(fmt.Sprintf("golang.platforms[%d]", idx), `golang.platforms must contain entries like "linux/amd64", %q is not allowed`, platform)
+		}
+	}
+	if len(c.Golang.Platforms) > 0 && len(c.Binaries) == 0 {
+		v.addError("golang.platforms", "golang.platforms requires at least one entry in binaries")
+	}
+
+	// Validate GolangciLintConfiguration.
+	if (len(c.GolangciLint.ErrcheckExcludes) > 0 || len(c.GolangciLint.ForbidigoRules) > 0 || len(c.GolangciLint.ReplaceAllowList) > 0) && !c.GolangciLint.CreateConfig {
+		v.addError("golangciLint.createConfig", "golangciLint.createConfig must be set to 'true' if golangciLint.errcheckExcludes, golangciLint.forbidigoRules or golangciLint.replaceAllowList is defined")
+	}
+
+	for idx, forbigoRule := range c.GolangciLint.ForbidigoRules {
+		if forbigoRule.Pkg == "" && forbigoRule.Pattern == "" {
+			v.addError(fmt.Sprintf("golangciLint.forbidigoRules[%d]", idx), "golangciLint.forbidigoRules must have at least pkg or pattern for each rule defined")
+		}
+	}
+
+	if c.ShellCheck.IsEnabled() {
+		for idx, path := range c.ShellCheck.IgnorePaths {
+			if strings.HasPrefix(path, "/") {
+				v.addError(fmt.Sprintf("shellCheck.ignorePaths[%d]", idx), "ShellCheck ignore paths must not start with a slash, got: %s", path)
+			}
+		}
+	}
+
+	for _, tool := range []struct {
+		Key     string
+		Version Option[string]
+	}{
+		{"addlicense", c.Tools.Addlicense},
+		{"controllerGen", c.Tools.ControllerGen},
+		{"goimports", c.Tools.Goimports},
+		{"goLicenceDetector", c.Tools.GoLicenceDetector},
+		{"golangciLint", c.Tools.GolangciLint},
+		{"setupEnvtest", c.Tools.SetupEnvtest},
+		{"shellcheck", c.Tools.Shellcheck},
+		{"typos", c.Tools.Typos},
+	} {
+		if version, ok := tool.Version.Unpack(); ok && !toolVersionRx.MatchString(version) {
+			v.addError("tools."+tool.Key, "tools.%s must be a version like \"v1.2.3\", %q is not allowed", tool.Key, version)
+		}
+	}
+
+	targetNames := make(map[string]bool)
+	for idx, target := range c.Makefile.Targets {
+		path := fmt.Sprintf("makefile.targets[%d]", idx)
+		switch {
+		case target.Name == "":
+			v.addError(path, "makefile.targets[].name must not be empty")
+		case strings.ContainsAny(target.Name, ":=#$ \t"):
+			v.addError(path+".name", "makefile.targets[].name must be a single target name, %q is not allowed", target.Name)
+		case targetNames[target.Name]:
+			v.addError(path+".name", "makefile.targets[].name must be unique, but %q is declared multiple times", target.Name)
+		}
+		targetNames[target.Name] = true
+		if !slices.Contains(MakefileCategories, target.GetCategory()) {
+			v.addError(path+".category", "unknown category for makefile.targets[]: %s (must be one of: %s)", target.GetCategory(), strings.Join(MakefileCategories, ", "))
+		}
+	}
+
+	// Validate GithubWorkflowConfiguration.
+	ghwCfg := c.GitHubWorkflow
+	if ghwCfg != nil {
+		if c.Metadata.URL == "" {
+			v.addError("githubWorkflow", "metadata.url must be set when any github workflow is configured otherwise it cannot be determined which github runner type should be used")
+		}
+
+		// Validate global options.
+		if ghwCfg.Global.DefaultBranch == "" {
+			branch, err := DetectDefaultBranch()
+			if err != nil {
+				v.addError("githubWorkflow.global.defaultBranch", "could not find default branch using git, you can define it manually by setting 'githubWorkflow.global.defaultBranch' in config: %s", err.Error())
+			} else {
+				c.GitHubWorkflow.Global.DefaultBranch = branch
+			}
+		}
+
+		// Validate CI workflow configuration.
+		if ghwCfg.CI.Enabled {
+			if len(ghwCfg.CI.RunsOn) > 1 && !strings.HasPrefix(ghwCfg.CI.RunsOn[0], "ubuntu") {
+				v.addError("githubWorkflow.ci.runOn", "githubWorkflow.ci.runOn must only define a single Ubuntu based runner when githubWorkflow.ci.enabled is true")
+			}
+		}
+
+		// Validate Release workflow configuration. Only flag explicit `releasePR: true`
+		// without a release workflow being rendered; the default-on case is silently
+		// inert when the release workflow itself is disabled.
+		if ghwCfg.Release.ReleasePR.UnwrapOr(false) && !ghwCfg.Release.IsEnabled(c.GoReleaser) {
+			v.addError("githubWorkflow.release.releasePR", "githubWorkflow.release.releasePR requires githubWorkflow.release.enabled (or goReleaser.createConfig) to be true")
+		}
+
+		for idx, strategy := range ghwCfg.PushContainerToGhcr.TagStrategy {
+			if !slices.Contains(TagStrategies, strategy) {
+				v.addError(fmt.Sprintf("githubWorkflow.pushContainerToGhcr.tagStrategy[%d]", idx), "unknown tagStrategy: %s (must be one of: %s)", strategy, strings.Join(TagStrategies, ", "))
+			}
+		}
+	}
+
+	// for SAP projects, we require the use of:
+	// - Renovate as a Software Composition Analysis tool
+	// - GitHub Advanced Security (specifically CodeQL) as a Security Check tool
+	// in order to satisfy compliance requirements
+	if c.Metadata.IsSAPProject() {
+		if ghwCfg != nil && !ghwCfg.SecurityChecks.IsEnabled() {
+			v.addError("githubWorkflow.securityChecks.enabled", "githubWorkflow.securityChecks.enabled may not be set to false (CodeQL is required for SAP projects to satisfy compliance requirements)")
+		}
+		if !c.Renovate.Enabled {
+			v.addError("renovate.enabled", "renovate.enabled must be set to true (Renovate is required for SAP projects to satisfy compliance requirements)")
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(c.VariableValues)) {
+		if slices.Contains([]string{"BININFO_VERSION", "BININFO_COMMIT_HASH", "BININFO_BUILD_DATE"}, key) {
+			v.addError("variables."+key, "variables cannot contain %s as it is reserved for build information", key)
+		}
+	}
+
+	return v.errs
+}
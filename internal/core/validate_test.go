@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"testing"
+)
+
+func TestParseConfigurationCollectsAllErrors(t *testing.T) {
+	buf := []byte(`metadata:
+  url: https://example.com/foo/bar
+binaries:
+  - name: foo
+    fromPackage: cmd/foo
+golang:
+  enableVendorin: true
+shellCheck:
+  ignorePaths: [ /tmp ]
+dockerfile:
+  enabled: notabool
+variables:
+  BININFO_VERSION: 1.0
+githubWorkflow:
+  global:
+    defaultBranch: main
+  pushContainerToGhcr:
+    tagStrategy:
+      - latest
+      - newest
+`)
+	_, _, errs := ParseConfiguration(buf)
+	assertErrors(t, errs,
+		"Makefile.maker.yaml:5:5: binaries[].fromPackage must begin with a dot, \"cmd/foo\" is not allowed!",
+		"Makefile.maker.yaml:7:3: unknown field golang.enableVendorin",
+		"Makefile.maker.yaml:9:18: ShellCheck ignore paths must not start with a slash, got: /tmp",
+		"Makefile.maker.yaml:11:3: invalid value for dockerfile.enabled: cannot unmarshal !!str `notabool` into bool",
+		"Makefile.maker.yaml:13:3: variables cannot contain BININFO_VERSION as it is reserved for build information",
+		"Makefile.maker.yaml:20:9: unknown tagStrategy: newest (must be one of: edge, latest, semver, sha)",
+	)
+}
+
+func TestParseConfigurationWithoutPosition(t *testing.T) {
+	// metadata.url does not exist, so the error points to the section that requires it
+	_, _, errs := ParseConfiguration([]byte("githubWorkflow:\n  global:\n    defaultBranch: main\n"))
+	assertErrors(t, errs, "Makefile.maker.yaml:1:1: metadata.url must be set when any github workflow is configured otherwise it cannot be determined which github runner type should be used")
+
+	_, _, errs = ParseConfiguration([]byte(""))
+	assertErrors(t, errs, "Makefile.maker.yaml: file is empty")
+
+	_, _, errs = ParseConfiguration([]byte("metadata:\n  url: [\n"))
+	if len(errs) != 1 || errs[0].Line == 0 {
+		t.Errorf("expected a single syntax error with a line number, but got %v", errs)
+	}
+}
+
+func TestValidateConfigSections(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Config         string
+		ExpectedErrors []string
+	}{
+		{
+			Name: "makefile targets",
+			Config: `makefile:
+  targets:
+    - name: generate
+    - description: no name
+    - name: generate
+      category: deploy
+    - name: "foo bar"
+`,
+			ExpectedErrors: []string{
+				"Makefile.maker.yaml:4:7: makefile.targets[].name must not be empty",
+				"Makefile.maker.yaml:5:7: makefile.targets[].name must be unique, but \"generate\" is declared multiple times",
+				"Makefile.maker.yaml:6:7: unknown category for makefile.targets[]: deploy (must be one of: general, prepare, build, test, development)",
+				"Makefile.maker.yaml:7:7: makefile.targets[].name must be a single target name, \"foo bar\" is not allowed",
+			},
+		},
+		{
+			Name: "tool versions",
+			Config: `tools:
+  goimports: v0.38.0
+  golangciLint: "v2.12.2; rm -rf /"
+  setupEnvtest: release-0.22
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:3:3: tools.golangciLint must be a version like "v1.2.3", "v2.12.2; rm -rf /" is not allowed`,
+			},
+		},
+		{
+			Name: "binary build settings",
+			Config: `binaries:
+  - name: example
+    fromPackage: .
+    buildTags: [ netgo, "sqlite,fts5" ]
+    ldflags: [ "-X 'main.foo=bar'" ]
+    env:
+      CGO_ENABLED: "1"
+      GOAMD64: v3
+      "FOO BAR": baz
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:4:25: binaries[].buildTags must only contain letters, digits, underscores and dots, "sqlite,fts5" is not allowed`,
+				`Makefile.maker.yaml:5:16: binaries[].ldflags must not contain single quotes, "-X 'main.foo=bar'" is not allowed`,
+				`Makefile.maker.yaml:7:7: binaries[].env must not contain CGO_ENABLED, use binaries[].cgo instead`,
+				`Makefile.maker.yaml:9:7: binaries[].env must only contain valid variable names, "FOO BAR" is not allowed`,
+			},
+		},
+		{
+			Name: "platforms",
+			Config: `golang:
+  platforms: [ linux/amd64, windows-arm64 ]
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:2:3: golang.platforms requires at least one entry in binaries`,
+				`Makefile.maker.yaml:2:29: golang.platforms must contain entries like "linux/amd64", "windows-arm64" is not allowed`,
+			},
+		},
+		{
+			Name: "fuzz time",
+			Config: `testPackages:
+  fuzz:
+    time: 1000x
+    ciTime: "10s; rm -rf /"
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:4:5: testPackages.fuzz.ciTime must be a duration like "30s" or a number of iterations like "1000x", "10s; rm -rf /" is not allowed`,
+			},
+		},
+		{
+			Name: "coverage minimums",
+			Config: `coverageTest:
+  minimum: 170
+  packageMinimums:
+    /internal/db$: 40
+    /internal/(api: 50
+    /internal/util: -1
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:2:3: coverageTest.minimum must be a percentage between 0 and 100, 170 is not allowed`,
+				"Makefile.maker.yaml:5:5: coverageTest.packageMinimums must have valid regexes as keys, \"/internal/(api\" is not allowed: error parsing regexp: missing closing ): `/internal/(api`",
+				`Makefile.maker.yaml:6:5: coverageTest.packageMinimums must have percentages between 0 and 100 as values, -1 is not allowed`,
+			},
+		},
+		{
+			Name: "test reports",
+			Config: `testPackages:
+  reports: [ junit, xml ]
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:2:21: testPackages.reports must only contain junit or json, "xml" is not allowed`,
+			},
+		},
+		{
+			Name: "integration tests",
+			Config: `testPackages:
+  integration:
+    enabled: true
+    tags: [ integration, "e2e || slow" ]
+    env:
+      DB_URL: "postgres://localhost/db?password='x'"
+      1FOO: bar
+    services: [ postgres, mysql ]
+`,
+			ExpectedErrors: []string{
+				`Makefile.maker.yaml:4:26: testPackages.integration.tags must only contain letters, digits, underscores and dots, "e2e || slow" is not allowed`,
+				`Makefile.maker.yaml:6:7: testPackages.integration.env must not contain single quotes, "postgres://localhost/db?password='x'" is not allowed`,
+				`Makefile.maker.yaml:7:7: testPackages.integration.env must only contain valid variable names, "1FOO" is not allowed`,
+				`Makefile.maker.yaml:8:27: unknown service in testPackages.integration.services: mysql (must be one of: postgres, redis)`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, _, errs := ParseConfiguration([]byte(tc.Config))
+			assertErrors(t, errs, tc.ExpectedErrors...)
+		})
+	}
+}
+
+// assertErrors checks that exactly the expected validation errors were reported, in this order.
+func assertErrors(t *testing.T, errs []ValidationError, expected ...string) {
+	t.Helper()
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, but got %d: %v", len(expected), len(errs), errs)
+	}
+	for idx, err := range errs {
+		if err.Error() != expected[idx] {
+			t.Errorf("expected error %d to be %q, but got %q", idx, expected[idx], err.Error())
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
@@ -25,6 +26,29 @@ var (
 	dockerignoreTemplate string
 )
 
+// Generator renders the Dockerfile and .dockerignore.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "dockerfile"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Dockerfile.Enabled
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config, ctx.ScanResult)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{"Dockerfile", ".dockerignore"}
+}
+
 // RenderConfig writes the docker configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 	// if there is an entrypoint configured use that otherwise fallback to the first binary name
@@ -83,24 +107,22 @@ func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 
 	var dockerHubMirror string
 	if strings.HasPrefix(cfg.Metadata.URL, "https://github.wdf.sap.corp") {
-		dockerHubMirror = "keppel.eu-de-1.cloud.sap/ccloud-dockerhub-mirror/library/"
+		dockerHubMirror = core.DockerHubMirror
 	}
 
 	// testing also includes building all binaries, so we must install the extra_build_packages during the test phase, too
 	extraTestPackages := slices.Clone(cfg.Dockerfile.ExtraBuildPackages)
 
 	// install additional runtime dependencies for linters and tests
-	reuseEnabled := cfg.Reuse.Enabled.UnwrapOr(true)
+	reuseEnabled := cfg.Reuse.IsEnabled()
 	if reuseEnabled {
 		extraTestPackages = append(extraTestPackages, "py3-pip")
 	}
-	if sr.UsesPostgres {
-		extraTestPackages = append(extraTestPackages, "postgresql")
+	for _, name := range sr.TestServices() {
+		extraTestPackages = append(extraTestPackages, core.TestServices[name].AlpinePackage)
 	}
 
-	crossCompile := cfg.Dockerfile.CrossCompile.UnwrapOr(
-		cfg.GitHubWorkflow != nil && strings.Contains(cfg.GitHubWorkflow.PushContainerToGhcr.Platforms, ","),
-	)
+	crossCompile := cfg.ShouldCrossCompile()
 
 	must.Succeed(util.WriteFileFromTemplate("Dockerfile", dockerfileTemplate, map[string]any{
 		"Config": cfg,
@@ -109,7 +131,7 @@ func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 			"DefaultAlpineImage": core.DefaultAlpineImage,
 		},
 		"DockerHubMirror":    dockerHubMirror,
-		"WithTestTarget":     cfg.Dockerfile.WithTestTarget.UnwrapOr(true),
+		"WithTestTarget":     cfg.Dockerfile.ShouldRenderTestTarget(),
 		"CheckEnv":           cfg.Dockerfile.CheckEnv,
 		"ExtraTestPackages":  extraTestPackages,
 		"Entrypoint":         entrypoint,
@@ -7,10 +7,11 @@ import (
 	_ "embed"
 	"maps"
 
-	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
@@ -19,19 +20,41 @@ var (
 	envrcTemplate string
 )
 
+// Generator renders the .envrc file.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "envrc"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.EnvRc.IsEnabled(cfg.Nix)
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderEnvRc(ctx.Config)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".envrc"}
+}
+
 // RenderEnvRc renders the .envrc file.
 func RenderEnvRc(cfg core.Configuration) {
-	if !cfg.EnvRc.Enabled.UnwrapOr(cfg.Nix.Enabled.UnwrapOr(true)) {
+	if !cfg.EnvRc.IsEnabled(cfg.Nix) {
 		return
 	}
-	logg.Debug("rendering envrc file")
 
 	variables := make(map[string]string)
 	maps.Copy(variables, cfg.VariableValues)
 	maps.Copy(variables, cfg.EnvRc.VariableValues)
 
 	must.Succeed(util.WriteFileFromTemplate(".envrc", envrcTemplate, map[string]any{
-		"NixEnabled": cfg.Nix.Enabled.UnwrapOr(true),
+		"NixEnabled": cfg.Nix.IsEnabled(),
 		"Variables":  variables,
 	}))
 }
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package generator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sapcc/go-bits/logg"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
+)
+
+// Generator renders one kind of generated file (or a group of closely related files).
+type Generator interface {
+	// Name identifies this generator in log messages and in DependsOn() of other generators.
+	Name() string
+	// Enabled returns whether Render shall be called for the given configuration.
+	Enabled(cfg core.Configuration, sr golang.ScanResult) bool
+	// Render writes the generated files through package util.
+	Render(ctx Context)
+	// OwnedFiles returns the paths of all files that Render writes for the given configuration.
+	OwnedFiles(cfg core.Configuration, sr golang.ScanResult) []string
+}
+
+// WithDependencies is an optional interface for generators that need to run after other generators,
+// e.g. because they read files rendered by those generators, or because they refer to those files.
+type WithDependencies interface {
+	Generator
+	// DependsOn returns the names of the generators that must run before this one.
+	DependsOn() []string
+}
+
+// WithValidation is an optional interface for generators that check parts of the configuration
+// which cannot be checked by core.Configuration.Validate(), e.g. because they depend on what the generator renders.
+type WithValidation interface {
+	Generator
+	// Validate reports problems with the configuration through the given function,
+	// along with the path of the offending field (like "makefile.targets[0].name").
+	Validate(cfg core.Configuration, sr golang.ScanResult, report func(path, msg string, args ...any))
+}
+
+// Context is passed to Generator.Render.
+type Context struct {
+	Config     core.Configuration
+	ScanResult golang.ScanResult
+
+	generator Generator
+	registry  *Registry
+}
+
+// OwnedFilesOf returns the files owned by the generator with the given name,
+// or nil if that generator is not enabled.
+// The generator must have been declared in DependsOn() of the calling generator.
+func (c Context) OwnedFilesOf(name string) []string {
+	if !slices.Contains(dependsOn(c.generator), name) {
+		logg.Fatal("generator %q needs to declare a dependency on generator %q", c.generator.Name(), name)
+	}
+	g := c.registry.find(name)
+	if !g.Enabled(c.Config, c.ScanResult) {
+		return nil
+	}
+	return g.OwnedFiles(c.Config, c.ScanResult)
+}
+
+func dependsOn(g Generator) []string {
+	if gd, ok := g.(WithDependencies); ok {
+		return gd.DependsOn()
+	}
+	return nil
+}
+
+// Registry holds a set of generators.
+type Registry struct {
+	generators []Generator
+}
+
+// Add adds generators to this registry.
+// Generators run in the order in which they were added,
+// except that each generator runs after all generators that it depends on.
+func (r *Registry) Add(generators ...Generator) {
+	for _, g := range generators {
+		if r.find(g.Name()) != nil {
+			logg.Fatal("cannot register multiple generators with the name %q", g.Name())
+		}
+		r.generators = append(r.generators, g)
+	}
+}
+
+func (r *Registry) find(name string) Generator {
+	for _, g := range r.generators {
+		if g.Name() == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// Validate calls Validate on all enabled generators that implement WithValidation, and returns all problems that were found.
+// The document that the configuration was read from is used to find the position of each problem.
+func (r *Registry) Validate(cfg core.Configuration, sr golang.ScanResult, doc core.Document) []core.ValidationError {
+	var errs []core.ValidationError
+	report := func(path, msg string, args ...any) {
+		errs = append(errs, doc.ErrorAt(path, fmt.Sprintf(msg, args...)))
+	}
+	for _, g := range r.Sorted() {
+		if gv, ok := g.(WithValidation); ok && g.Enabled(cfg, sr) {
+			gv.Validate(cfg, sr, report)
+		}
+	}
+	return errs
+}
+
+// Run calls Render on all enabled generators in dependency order.
+// Afterwards, files that were generated in a previous run, but not in this one, are removed
+// and the manifest at ManifestPath is updated.
+func (r *Registry) Run(cfg core.Configuration, sr golang.ScanResult) {
+	var (
+		written            = make(manifest)
+		disabledGenerators []string
+	)
+	for _, g := range r.Sorted() {
+		if !g.Enabled(cfg, sr) {
+			logg.Debug("skipping %s (not enabled)", g.Name())
+			disabledGenerators = append(disabledGenerators, g.Name())
+			continue
+		}
+
+		logg.Debug("rendering %s", g.Name())
+		paths := util.RecordWrites(func() {
+			g.Render(Context{
+				Config:     cfg,
+				ScanResult: sr,
+				generator:  g,
+				registry:   r,
+			})
+		})
+		if len(paths) == 0 {
+			continue
+		}
+
+		ownedFiles := g.OwnedFiles(cfg, sr)
+		for _, path := range paths {
+			if !slices.Contains(ownedFiles, path) {
+				logg.Fatal("generator %q wrote %s, but does not declare it in OwnedFiles()", g.Name(), path)
+			}
+		}
+		slices.Sort(paths)
+		written[g.Name()] = slices.Compact(paths)
+	}
+
+	updateManifest(written, disabledGenerators)
+}
+
+// Sorted returns all generators in the order in which Run calls them.
+func (r *Registry) Sorted() []Generator {
+	var (
+		result []Generator
+		// value is false while visiting the dependencies of a generator, and true once it was appended to result
+		visited = make(map[string]bool)
+		visit   func(g Generator, path []string)
+	)
+	visit = func(g Generator, path []string) {
+		done, seen := visited[g.Name()]
+		if done {
+			return
+		}
+		path = append(path, g.Name())
+		if seen {
+			logg.Fatal("dependency cycle between generators: %v", path)
+		}
+		visited[g.Name()] = false
+		for _, name := range dependsOn(g) {
+			dep := r.find(name)
+			if dep == nil {
+				logg.Fatal("generator %q depends on unknown generator %q", g.Name(), name)
+			}
+			visit(dep, path)
+		}
+		visited[g.Name()] = true
+		result = append(result, g)
+	}
+	for _, g := range r.generators {
+		visit(g, nil)
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package generator
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sapcc/go-bits/must"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
+)
+
+type fakeGenerator struct {
+	name      string
+	enabled   bool
+	dependsOn []string
+	// filled by Render
+	renderLog   *[]string
+	filesOfDeps map[string][]string
+}
+
+func (g fakeGenerator) Name() string { return g.name }
+
+func (g fakeGenerator) Enabled(_ core.Configuration, _ golang.ScanResult) bool { return g.enabled }
+
+func (g fakeGenerator) Render(ctx Context) {
+	*g.renderLog = append(*g.renderLog, g.name)
+	must.Succeed(util.WriteFile(g.name+".txt", []byte(g.name)))
+	for _, dep := range g.dependsOn {
+		g.filesOfDeps[dep] = ctx.OwnedFilesOf(dep)
+	}
+}
+
+func (g fakeGenerator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{g.name + ".txt"}
+}
+
+func (g fakeGenerator) DependsOn() []string { return g.dependsOn }
+
+func TestRegistryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var renderLog []string
+	filesOfDeps := make(map[string][]string)
+	newGenerator := func(name string, enabled bool, dependsOn ...string) Generator {
+		return fakeGenerator{name, enabled, dependsOn, &renderLog, filesOfDeps}
+	}
+
+	var r Registry
+	r.Add(
+		newGenerator("first", true, "third", "disabled"),
+		newGenerator("second", true),
+		newGenerator("third", true, "second"),
+		newGenerator("disabled", false),
+	)
+
+	var sortedNames []string
+	for _, g := range r.Sorted() {
+		sortedNames = append(sortedNames, g.Name())
+	}
+	expected := []string{"second", "third", "disabled", "first"}
+	if !slices.Equal(sortedNames, expected) {
+		t.Errorf("expected generators to be sorted as %v, but got %v", expected, sortedNames)
+	}
+
+	r.Run(core.Configuration{}, golang.ScanResult{})
+	expected = []string{"second", "third", "first"}
+	if !slices.Equal(renderLog, expected) {
+		t.Errorf("expected generators to run in order %v, but got %v", expected, renderLog)
+	}
+	if files := filesOfDeps["third"]; !slices.Equal(files, []string{"third.txt"}) {
+		t.Errorf("expected owned files of enabled dependency to be reported, but got %v", files)
+	}
+	if files := filesOfDeps["disabled"]; files != nil {
+		t.Errorf("expected no owned files for disabled dependency, but got %v", files)
+	}
+}
+
+func TestManifestCleanup(t *testing.T) {
+	t.Chdir(t.TempDir())
+	var renderLog []string
+	newGenerator := func(name string, enabled bool) Generator {
+		return fakeGenerator{name, enabled, nil, &renderLog, nil}
+	}
+
+	// first run: all generators write their files
+	var r Registry
+	r.Add(newGenerator("kept", true), newGenerator("obsolete", true), newGenerator("disabled", true))
+	r.Run(core.Configuration{}, golang.ScanResult{})
+
+	// second run: one generator is gone, one is disabled
+	r = Registry{}
+	r.Add(newGenerator("kept", true), newGenerator("disabled", false))
+	r.Run(core.Configuration{}, golang.ScanResult{})
+
+	for path, shouldExist := range map[string]bool{
+		"kept.txt":     true,
+		"obsolete.txt": false,
+		// files of disabled generators are left alone
+		"disabled.txt": true,
+	} {
+		_, err := os.Stat(path)
+		if exists := err == nil; exists != shouldExist {
+			t.Errorf("expected existence of %s to be %t, but got %t", path, shouldExist, exists)
+		}
+	}
+
+	buf := string(must.Return(os.ReadFile(ManifestPath)))
+	if !strings.HasSuffix(buf, "\nkept:\n  - kept.txt\n") {
+		t.Errorf("unexpected manifest contents:\n%s", buf)
+	}
+}
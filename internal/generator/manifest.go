@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/util"
+)
+
+// ManifestPath is the path of the file that lists all files written by the generators.
+const ManifestPath = ".go-makefile-maker.lock"
+
+// manifest maps generator names to the paths of the files written by that generator.
+type manifest map[string][]string
+
+// legacyManifest lists files that were generated by versions of go-makefile-maker predating the manifest.
+// It stands in for the manifest if none exists yet, so that these files get cleaned up like any other stale file.
+//
+// This list is frozen. Files that are not generated anymore are cleaned up through the manifest.
+var legacyManifest = manifest{
+	"github-workflows": {
+		".github/workflows/codeql.yml",
+		".github/workflows/dependency-review.yaml",
+		".github/workflows/license.yaml",
+		".github/workflows/spell.yaml",
+	},
+	"renovate": {
+		".github/renovate.json",
+		"renovate.json",
+	},
+}
+
+func readManifest() manifest {
+	buf, err := util.ReadFile(ManifestPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return legacyManifest
+	}
+	must.Succeed(err)
+
+	var m manifest
+	err = yaml.Unmarshal(buf, &m)
+	if err != nil {
+		logg.Fatal("cannot parse %s: %s", ManifestPath, err.Error())
+	}
+	return m
+}
+
+// updateManifest removes all files listed in the previous manifest that were not written in this run,
+// and then writes the new manifest.
+//
+// Files of generators that are disabled in this run are not removed, but they are also not listed in the new manifest.
+// When a generator gets disabled, the user takes over ownership of its files.
+func updateManifest(written manifest, disabledGenerators []string) {
+	isWritten := make(map[string]bool)
+	for _, paths := range written {
+		for _, path := range paths {
+			isWritten[path] = true
+		}
+	}
+
+	oldManifest := readManifest()
+	for _, name := range slices.Sorted(maps.Keys(oldManifest)) {
+		if slices.Contains(disabledGenerators, name) {
+			continue
+		}
+		for _, path := range oldManifest[name] {
+			if isWritten[path] {
+				continue
+			}
+			if !filepath.IsLocal(path) {
+				logg.Fatal("refusing to remove %q (listed in %s) since it is not a path within the repository", path, ManifestPath)
+			}
+			logg.Debug("removing %s since it is not generated anymore", path)
+			must.Succeed(util.RemoveFile(path))
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, core.AutogeneratedHeader)
+	fmt.Fprintln(&buf, "# This file lists all files generated by go-makefile-maker.")
+	fmt.Fprintln(&buf, "# Files listed here are removed automatically once they are not generated anymore.")
+	fmt.Fprintln(&buf)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	must.Succeed(encoder.Encode(written))
+	must.Succeed(encoder.Close())
+	must.Succeed(util.WriteFile(ManifestPath, buf.Bytes()))
+}
@@ -4,67 +4,75 @@
 package ghworkflow
 
 import (
+	"bytes"
 	"fmt"
-	"os"
-	"path/filepath"
+	"slices"
 
-	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/must"
-	. "go.xyrillian.de/gg/option"
 	"go.yaml.in/yaml/v3"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
 const workflowDir = ".github/workflows"
 
-// Render renders GitHub workflows.
-// Returns a list of filenames for all files that were generated.
-func Render(cfg core.Configuration, sr golang.ScanResult) []string {
-	ghwCfg := cfg.GitHubWorkflow
+// Generator renders GitHub workflows.
+type Generator struct{}
 
-	must.Succeed(os.MkdirAll(workflowDir, 0o755))
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "github-workflows"
+}
 
-	// remove renamed files
-	must.Succeed(os.RemoveAll(filepath.Join(workflowDir, "codeql.yml")))
-	must.Succeed(os.RemoveAll(filepath.Join(workflowDir, "dependency-review.yaml")))
-	must.Succeed(os.RemoveAll(filepath.Join(workflowDir, "license.yaml")))
-	must.Succeed(os.RemoveAll(filepath.Join(workflowDir, "spell.yaml")))
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.GitHubWorkflow != nil
+}
 
-	// TODO: checking on GoVersion is only an aid until we can properly detect rust applications
-	var allWorkflows []Option[workflow]
-	if sr.GoVersion != "" {
-		allWorkflows = append(allWorkflows, checksWorkflow(cfg))
-		allWorkflows = append(allWorkflows, ciWorkflow(cfg, sr))
-		allWorkflows = append(allWorkflows, codeQLWorkflow(cfg))
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	for _, w := range allWorkflows(ctx.Config, ctx.ScanResult) {
+		writeWorkflowToFile(w)
 	}
-	allWorkflows = append(allWorkflows, helmWorkflow(cfg))
-	allWorkflows = append(allWorkflows, ghcrWorkflow(ghwCfg))
-	allWorkflows = append(allWorkflows, releaseWorkflow(cfg))
-	allWorkflows = append(allWorkflows, releasePRWorkflow(cfg))
+}
 
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(cfg core.Configuration, sr golang.ScanResult) []string {
 	var result []string
-	for _, workflowOrNone := range allWorkflows {
-		w, ok := workflowOrNone.Unpack()
-		if ok {
-			writeWorkflowToFile(w)
-			result = append(result, w.getPath())
-		}
+	for _, w := range allWorkflows(cfg, sr) {
+		result = append(result, w.getPath())
 	}
 	return result
 }
 
+// allWorkflows returns all workflows that are enabled.
+// Workflows that were generated before, but are disabled now, are removed through the manifest of generated files.
+func allWorkflows(cfg core.Configuration, sr golang.ScanResult) []workflow {
+	var result []workflow
+	// TODO: checking on GoVersion is only an aid until we can properly detect rust applications
+	if sr.GoVersion != "" {
+		result = append(result, checksWorkflow(cfg, sr))
+		result = append(result, ciWorkflow(cfg, sr))
+		result = append(result, codeQLWorkflow(cfg))
+	}
+	result = append(result, helmWorkflow(cfg))
+	result = append(result, ghcrWorkflow(cfg.GitHubWorkflow, cfg.Golang.Reproducible))
+	result = append(result, releaseWorkflow(cfg))
+	result = append(result, releasePRWorkflow(cfg))
+	return slices.DeleteFunc(result, func(w workflow) bool { return w.isDisabled })
+}
+
 func writeWorkflowToFile(w workflow) {
-	path := w.getPath()
-	logg.Debug("-> writing file %s", path)
-	f := must.Return(os.Create(path))
-	defer f.Close()
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, core.AutogeneratedHeader)
 
-	encoder := yaml.NewEncoder(f)
-	defer encoder.Close()
+	encoder := yaml.NewEncoder(&buf)
 	encoder.SetIndent(2)
-
-	fmt.Fprintln(f, core.AutogeneratedHeader)
 	must.Succeed(encoder.Encode(w))
+	must.Succeed(encoder.Close())
+
+	must.Succeed(util.WriteFile(w.getPath(), buf.Bytes()))
 }
@@ -4,12 +4,9 @@
 package ghworkflow
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/sapcc/go-bits/must"
-
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
@@ -32,6 +29,9 @@ type workflow struct {
 	Permissions permissions `yaml:"permissions"`
 	// A map of <job_id> to their configuration(s).
 	Jobs map[string]job `yaml:"jobs"`
+
+	// If true, the workflow file is removed instead of being rendered.
+	isDisabled bool
 }
 
 func (w workflow) getPath() string {
@@ -39,13 +39,9 @@ func (w workflow) getPath() string {
 	return filepath.Join(workflowDir, fileName+".yaml")
 }
 
-func (w workflow) deleteUnless(condition bool) bool {
-	if !condition {
-		must.Succeed(os.RemoveAll(w.getPath()))
-		return true
-	}
-
-	return false
+func (w *workflow) disableUnless(condition bool) bool {
+	w.isDisabled = !condition
+	return w.isDisabled
 }
 
 type githubTokenScope string
@@ -4,28 +4,35 @@
 package ghworkflow
 
 import (
-	. "go.xyrillian.de/gg/option"
-
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
 )
 
 // This workflow contains only linters and checks which run fast.
 // It runs before the other workflows to reduce the amount of created GitHub Action workflows in case of basic errors.
-func checksWorkflow(cfg core.Configuration) Option[workflow] {
+func checksWorkflow(cfg core.Configuration, sr golang.ScanResult) workflow {
 	ghwCfg := cfg.GitHubWorkflow
 	w := newWorkflow("Checks", ghwCfg.Global.DefaultBranch, nil)
 	w.On.WorkflowDispatch.manualTrigger = true
 	j := baseJobWithGo("Checks", cfg)
 
-	// see https://github.com/golangci/golangci-lint-action#annotations
-	w.Permissions.Checks = tokenScopeWrite
-	j.addStep(jobStep{
-		Name: "Run golangci-lint",
-		Uses: core.GolangciLintAction,
-		With: map[string]any{
-			"version": core.GolangCiLintVersion,
-		},
-	})
+	if sr.HasGoTool("golangci-lint") {
+		// the version is pinned in go.mod, so there is no need for the action to install golangci-lint
+		j.addStep(jobStep{
+			Name: "Run golangci-lint",
+			Run:  "make run-golangci-lint",
+		})
+	} else {
+		// see https://github.com/golangci/golangci-lint-action#annotations
+		w.Permissions.Checks = tokenScopeWrite
+		j.addStep(jobStep{
+			Name: "Run golangci-lint",
+			Uses: core.GolangciLintAction,
+			With: map[string]any{
+				"version": cfg.Tools.GetGolangciLintVersion(),
+			},
+		})
+	}
 
 	if cfg.ShellCheck.IsEnabled() {
 		// delete the pretty out of date installed version of shellcheck so that make install-shellcheck installs the current version
@@ -71,5 +78,5 @@ func checksWorkflow(cfg core.Configuration) Option[workflow] {
 	}
 
 	w.Jobs = map[string]job{"checks": j}
-	return Some(w)
+	return w
 }
@@ -5,14 +5,14 @@ package ghworkflow
 
 import (
 	"fmt"
-
-	. "go.xyrillian.de/gg/option"
+	"maps"
+	"strings"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 )
 
-func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) Option[workflow] {
+func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) workflow {
 	ghwCfg := cfg.GitHubWorkflow
 	ignorePaths := ghwCfg.CI.IgnorePaths
 	if len(ignorePaths) == 0 {
@@ -23,8 +23,8 @@ func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) Option[workflow] {
 	w.On.WorkflowDispatch.manualTrigger = true
 	w.On.Push.Branches = []string{ghwCfg.Global.DefaultBranch}
 
-	if w.deleteUnless(ghwCfg.CI.Enabled) {
-		return None[workflow]()
+	if w.disableUnless(ghwCfg.CI.Enabled) {
+		return w
 	}
 
 	containerImage := fmt.Sprintf("keppel.eu-de-1.cloud.sap/ccloud/shared-base-images/golang-alpine-ci:%s-latest", sr.GoVersionMajorMinor)
@@ -54,21 +54,27 @@ func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) Option[workflow] {
 		testJob.Container.Options = containerOption
 	}
 	testJob.Needs = []string{"build"}
-	testCmd := []string{
-		"make build/cover.out",
-	}
-	// Self-hosted runners use an Alpine Docker container where Postgres is already installed
-	if sr.UsesPostgres && !cfg.GitHubWorkflow.IsSelfHostedRunner {
-		testCmd = append([]string{
-			"sudo /usr/share/postgresql-common/pgdg/apt.postgresql.org.sh -y",
-			"sudo apt-get install -y --no-install-recommends postgresql-" + core.DefaultPostgresVersion,
-			fmt.Sprintf("export PATH=/usr/lib/postgresql/%s/bin:$PATH", core.DefaultPostgresVersion),
-		}, testCmd...)
+	// withTestServices prepends the installation of the services that the unit tests need locally (see golang.ScanResult.TestServices)
+	withTestServices := func(testCmd ...string) []string {
+		// Self-hosted runners use an Alpine Docker container where these services are already installed
+		if cfg.GitHubWorkflow.IsSelfHostedRunner {
+			return testCmd
+		}
+		var setupCmd []string
+		for _, name := range sr.TestServices() {
+			setupCmd = append(setupCmd, core.TestServices[name].UbuntuSetup...)
+		}
+		return append(setupCmd, testCmd...)
 	}
-	testJob.addStep(jobStep{
+	testStep := jobStep{
 		Name: "Run tests and generate coverage report",
-		Run:  makeMultilineYAMLString(testCmd),
-	})
+		Run:  makeMultilineYAMLString(withTestServices("make build/cover.out")),
+	}
+	if len(cfg.Test.Reports) > 0 {
+		// the outcome of this step is reported in the check run for the test report below
+		testStep.ID = "test"
+	}
+	testJob.addStep(testStep)
 
 	// see https://github.com/fgrosse/go-coverage-report#usage
 	coverageArtifactName := "code-coverage"
@@ -83,8 +89,91 @@ func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) Option[workflow] {
 		},
 	})
 
+	if len(cfg.Test.Reports) > 0 {
+		var reportPaths []string
+		if cfg.Test.HasReport("junit") {
+			reportPaths = append(reportPaths, "build/test-report.xml")
+		}
+		if cfg.Test.HasReport("json") {
+			reportPaths = append(reportPaths, "build/test-report.json")
+		}
+		testJob.addStep(jobStep{
+			Name: "Archive test reports",
+			// test reports are most interesting when tests have failed
+			If:   "!cancelled()",
+			Uses: core.GetUploadArtifactAction(ghwCfg.IsSelfHostedRunner),
+			With: map[string]any{
+				"name": "test-reports",
+				"path": makeMultilineYAMLString(reportPaths),
+			},
+		})
+
+		// the check run is created with the GitHub CLI, which is only preinstalled on github.com runners;
+		// on pull requests from forks, the token does not have the permission to create check runs
+		if cfg.Test.HasReport("junit") && !ghwCfg.IsSelfHostedRunner {
+			testJob.Permissions = permissions{
+				Checks:   tokenScopeWrite,
+				Contents: tokenScopeRead,
+			}
+			testJob.addStep(jobStep{
+				Name: "Publish test report",
+				If:   "!cancelled() && hashFiles('build/test-report.xml') != '' && (github.event_name != 'pull_request' || github.event.pull_request.head.repo.full_name == github.repository)",
+				Env: map[string]string{
+					"CONCLUSION": "${{ steps.test.outcome }}",
+					"GH_TOKEN":   "${{ github.token }}",
+					"HEAD_SHA":   "${{ github.event.pull_request.head.sha || github.sha }}",
+				},
+				Run: makeMultilineYAMLString([]string{
+					"go run " + core.TestReportHelperPath + " -summary build/test-report.xml > build/test-report.md",
+					`gh api "repos/${GITHUB_REPOSITORY}/check-runs" -f name="Test report" -f head_sha="${HEAD_SHA}" -f status=completed -f conclusion="${CONCLUSION}" -f "output[title]=Test report" -F "output[summary]=@build/test-report.md"`,
+				}),
+			})
+		}
+	}
+
+	if cfg.Coverage.HasMinimums() {
+		// this also posts the table of packages below their minimum coverage on the summary page of the workflow run,
+		// which is especially relevant on self-hosted runners where the code coverage report below is not available
+		testJob.addStep(jobStep{
+			Name: "Check test coverage",
+			Run:  "make check-coverage",
+		})
+	}
+
 	w.Jobs["test"] = testJob
 
+	if cfg.Test.Race {
+		raceJob := baseJobWithGo("Race detector", cfg)
+		if cfg.GitHubWorkflow.IsSelfHostedRunner {
+			raceJob.Container.Image = containerImage
+			raceJob.Container.Options = containerOption
+		}
+		raceJob.Needs = []string{"build"}
+		raceJob.addStep(jobStep{
+			Name: "Run tests with race detector",
+			Run:  makeMultilineYAMLString(withTestServices("make check-race")),
+		})
+		w.Jobs["race"] = raceJob
+	}
+
+	if cfg.Test.Integration.Enabled {
+		w.Jobs["integration"] = integrationJob(cfg, withTestServices("make check-integration"), containerImage, containerOption)
+	}
+
+	if fuzzTime := cfg.Test.Fuzz.CITime; fuzzTime != "" {
+		fuzzJob := baseJobWithGo("Fuzz", cfg)
+		if cfg.GitHubWorkflow.IsSelfHostedRunner {
+			fuzzJob.Container.Image = containerImage
+			fuzzJob.Container.Options = containerOption
+		}
+		fuzzJob.Needs = []string{"build"}
+		fuzzJob.addStep(jobStep{
+			Name: "Run fuzz tests",
+			Run:  "make fuzz FUZZTIME=" + fuzzTime,
+		})
+		w.Jobs["fuzz"] = fuzzJob
+	}
+
 	// coverage is only available on github.com because tj-actions/changed-files is blocked due to their famour securits incident
 	if !ghwCfg.IsSelfHostedRunner {
 		// see https://github.com/fgrosse/go-coverage-report#usage
@@ -111,5 +200,49 @@ func ciWorkflow(cfg core.Configuration, sr golang.ScanResult) Option[workflow] {
 		w.Jobs["code_coverage"] = codeCov
 	}
 
-	return Some(w)
+	return w
+}
+
+// integrationJob returns the job that runs the integration tests with the services from testPackages.integration.services.
+func integrationJob(cfg core.Configuration, testCmd []string, containerImage, containerOption string) job {
+	j := baseJobWithGo("Integration tests", cfg)
+	// service containers are reachable via localhost on the runner itself, but by their name from inside a job container
+	useContainer := cfg.GitHubWorkflow.IsSelfHostedRunner
+	if useContainer {
+		j.Container.Image = containerImage
+		j.Container.Options = containerOption
+	}
+	j.Needs = []string{"build"}
+
+	if services := cfg.Test.Integration.Services; len(services) > 0 {
+		j.Services = make(map[string]jobService, len(services))
+		j.Env = make(map[string]string)
+		for _, name := range services {
+			svc := core.TestServices[name]
+			image := svc.Image
+			if strings.HasPrefix(cfg.Metadata.URL, "https://github.wdf.sap.corp") {
+				image = core.DockerHubMirror + image
+			}
+			host := "localhost"
+			var ports []string
+			if useContainer {
+				host = name
+			} else {
+				ports = []string{fmt.Sprintf("%[1]d:%[1]d", svc.Port)}
+			}
+			j.Services[name] = jobService{
+				Image:   image,
+				Env:     svc.Env,
+				Ports:   ports,
+				Options: fmt.Sprintf("--health-cmd %q --health-interval 5s --health-timeout 5s --health-retries 10", svc.HealthCheck),
+			}
+			maps.Copy(j.Env, svc.TestEnvFor(host))
+		}
+	}
+
+	j.addStep(jobStep{
+		Name: "Run integration tests",
+		Run:  makeMultilineYAMLString(testCmd),
+	})
+	return j
 }
@@ -4,18 +4,16 @@
 package ghworkflow
 
 import (
-	. "go.xyrillian.de/gg/option"
-
 	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
-func codeQLWorkflow(cfg core.Configuration) Option[workflow] {
+func codeQLWorkflow(cfg core.Configuration) workflow {
 	ghwCfg := cfg.GitHubWorkflow
 	w := newWorkflow("CodeQL", ghwCfg.Global.DefaultBranch, nil)
 	w.On.WorkflowDispatch.manualTrigger = true
 
-	if w.deleteUnless(ghwCfg.SecurityChecks.IsEnabled()) {
-		return None[workflow]()
+	if w.disableUnless(ghwCfg.SecurityChecks.IsEnabled()) {
+		return w
 	}
 
 	w.Permissions.Actions = tokenScopeRead         // for github/codeql-action/init to get workflow details
@@ -32,7 +30,7 @@ func codeQLWorkflow(cfg core.Configuration) Option[workflow] {
 		Uses: core.GetCodeqlInitAction(ghwCfg.IsSelfHostedRunner),
 		With: map[string]any{
 			"languages": "go",
-			"queries":   cfg.GitHubWorkflow.SecurityChecks.Queries.UnwrapOr("security-extended"),
+			"queries":   cfg.GitHubWorkflow.SecurityChecks.GetQueries(),
 		},
 	})
 	j.addStep(jobStep{
@@ -45,5 +43,5 @@ func codeQLWorkflow(cfg core.Configuration) Option[workflow] {
 	})
 
 	w.Jobs = map[string]job{"analyze": j}
-	return Some(w)
+	return w
 }
@@ -5,20 +5,16 @@ package ghworkflow
 
 import (
 	"slices"
-	"strings"
-
-	"github.com/sapcc/go-bits/logg"
-	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
-func ghcrWorkflow(cfg *core.GithubWorkflowConfiguration) Option[workflow] {
+func ghcrWorkflow(cfg *core.GithubWorkflowConfiguration, reproducible bool) workflow {
 	// https://docs.github.com/en/packages/managing-github-packages-using-github-actions-workflows/publishing-and-installing-a-package-with-github-actions#publishing-a-package-using-an-action
 	w := newWorkflow("Container Registry GHCR", cfg.Global.DefaultBranch, nil)
 
-	if w.deleteUnless(cfg.PushContainerToGhcr.Enabled) {
-		return None[workflow]()
+	if w.disableUnless(cfg.PushContainerToGhcr.Enabled) {
+		return w
 	}
 
 	w.Permissions.Contents = tokenScopeRead
@@ -50,25 +46,16 @@ func ghcrWorkflow(cfg *core.GithubWorkflowConfiguration) Option[workflow] {
 
 	var tags string
 	if slices.Contains(strategy, "edge") {
-		strategy = slices.DeleteFunc(strategy, func(s string) bool {
-			return s == "edge"
-		})
 		tags += `# https://github.com/docker/metadata-action#typeedge
 type=edge
 `
 	}
 	if slices.Contains(strategy, "latest") {
-		strategy = slices.DeleteFunc(strategy, func(s string) bool {
-			return s == "latest"
-		})
 		tags += `# https://github.com/docker/metadata-action#latest-tag
 type=raw,value=latest,enable={{is_default_branch}}
 `
 	}
 	if slices.Contains(strategy, "semver") {
-		strategy = slices.DeleteFunc(strategy, func(s string) bool {
-			return s == "semver"
-		})
 		tags += `# https://github.com/docker/metadata-action#typesemver
 type=semver,pattern={{raw}}
 type=semver,pattern=v{{major}}.{{minor}}
@@ -76,18 +63,11 @@ type=semver,pattern=v{{major}}
 `
 	}
 	if slices.Contains(strategy, "sha") {
-		strategy = slices.DeleteFunc(strategy, func(s string) bool {
-			return s == "sha"
-		})
 		tags += `# https://github.com/docker/metadata-action#typesha
 type=sha,format=long
 `
 	}
 
-	if len(strategy) != 0 {
-		logg.Fatal("unknown tagStrategy: %s", strings.Join(strategy, ", "))
-	}
-
 	j.addStep(jobStep{
 		Name: "Extract metadata (tags, labels) for Docker",
 		ID:   "meta",
@@ -110,7 +90,7 @@ type=sha,format=long
 	if platforms == "" {
 		platforms = "linux/amd64"
 	}
-	j.addStep(jobStep{
+	buildStep := jobStep{
 		Name: "Build and push Docker image",
 		Uses: core.DockerBuildPushAction,
 		With: map[string]any{
@@ -120,7 +100,26 @@ type=sha,format=long
 			"labels":    "${{ steps.meta.outputs.labels }}",
 			"platforms": platforms,
 		},
-	})
+	}
+	if reproducible {
+		// use the same build date as `make` would derive from the commit timestamp (see golang.reproducible)
+		j.addStep(jobStep{
+			Name: "Determine build information",
+			ID:   "bininfo",
+			Run: makeMultilineYAMLString([]string{
+				`echo "build-date=$(TZ=UTC git log -1 --format=%cd --date=format-local:%Y-%m-%dT%H:%M:%SZ)" >> "$GITHUB_OUTPUT"`,
+				`echo "source-date-epoch=$(git log -1 --format=%ct)" >> "$GITHUB_OUTPUT"`,
+			}),
+		})
+		buildStep.With["build-args"] = makeMultilineYAMLString([]string{
+			"BININFO_BUILD_DATE=${{ steps.bininfo.outputs.build-date }}",
+			"BININFO_COMMIT_HASH=${{ github.sha }}",
+			"SOURCE_DATE_EPOCH=${{ steps.bininfo.outputs.source-date-epoch }}",
+		})
+		// also makes BuildKit use this timestamp for the image layers
+		buildStep.Env = map[string]string{"SOURCE_DATE_EPOCH": "${{ steps.bininfo.outputs.source-date-epoch }}"}
+	}
+	j.addStep(buildStep)
 
 	w.Jobs = map[string]job{"build-and-push-image": j}
 
@@ -142,5 +141,5 @@ type=sha,format=long
 		w.Jobs["cleanup-untagged-versions"] = cleanupJob
 	}
 
-	return Some(w)
+	return w
 }
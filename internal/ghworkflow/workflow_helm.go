@@ -7,18 +7,16 @@ import (
 	"slices"
 	"strings"
 
-	. "go.xyrillian.de/gg/option"
-
 	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
-func helmWorkflow(cfg core.Configuration) Option[workflow] {
+func helmWorkflow(cfg core.Configuration) workflow {
 	// https://docs.github.com/en/packages/managing-github-packages-using-github-actions-workflows/publishing-and-installing-a-package-with-github-actions#publishing-a-package-using-an-action
 	w := newWorkflow("Helm OCI Package GHCR", cfg.GitHubWorkflow.Global.DefaultBranch, nil)
 
-	if w.deleteUnless(cfg.GitHubWorkflow.PushHelmChartToGhcr.Path.IsSome() &&
+	if w.disableUnless(cfg.GitHubWorkflow.PushHelmChartToGhcr.Path.IsSome() &&
 		strings.HasPrefix(cfg.Metadata.URL, "https://github.com")) {
-		return None[workflow]()
+		return w
 	}
 
 	var helmConfig = cfg.GitHubWorkflow.PushHelmChartToGhcr
@@ -75,7 +73,7 @@ func helmWorkflow(cfg core.Configuration) Option[workflow] {
 		w.On.Push.Paths = []string{chartPath + "/**"}
 	}
 
-	if helmConfig.DependencyUpdate.UnwrapOr(true) {
+	if helmConfig.ShouldUpdateDependencies() {
 		helmPackageCmds = append(helmPackageCmds, `HELM_ARGS=--dependency-update`)
 	}
 
@@ -89,7 +87,7 @@ func helmWorkflow(cfg core.Configuration) Option[workflow] {
 		Name: "Install Helm",
 		Uses: core.HelmSetupAction,
 	})
-	if helmConfig.Lint.UnwrapOr(true) {
+	if helmConfig.ShouldLint() {
 		j.addStep(jobStep{
 			Name: "Lint Helm Chart",
 			Run:  "helm lint " + chartPath,
@@ -124,5 +122,5 @@ func helmWorkflow(cfg core.Configuration) Option[workflow] {
 	})
 
 	w.Jobs = map[string]job{"build-and-push-helm-package": j}
-	return Some(w)
+	return w
 }
@@ -4,8 +4,6 @@
 package ghworkflow
 
 import (
-	. "go.xyrillian.de/gg/option"
-
 	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
@@ -14,16 +12,16 @@ import (
 // same value to filter for that PR's merge event.
 const releasePRBranch = "chore/release-next"
 
-func releaseWorkflow(cfg core.Configuration) Option[workflow] {
+func releaseWorkflow(cfg core.Configuration) workflow {
 	// https://docs.github.com/en/packages/managing-github-packages-using-github-actions-workflows/publishing-and-installing-a-package-with-github-actions#publishing-a-package-using-an-action
 	ghwCfg := cfg.GitHubWorkflow
 	w := newWorkflow("goreleaser", ghwCfg.Global.DefaultBranch, nil)
 
-	if w.deleteUnless(ghwCfg.Release.Enabled.UnwrapOr(cfg.GoReleaser.ShouldCreateConfig())) {
-		return None[workflow]()
+	if w.disableUnless(ghwCfg.Release.IsEnabled(cfg.GoReleaser)) {
+		return w
 	}
 
-	releasePR := ghwCfg.Release.ReleasePR.UnwrapOr(true)
+	releasePR := ghwCfg.Release.IsReleasePREnabled(cfg.GoReleaser)
 
 	w.Permissions.Contents = tokenScopeWrite
 	w.Permissions.Packages = tokenScopeWrite
@@ -82,7 +80,7 @@ func releaseWorkflow(cfg core.Configuration) Option[workflow] {
 	if releasePR {
 		w.Jobs["tag"] = tagJob(ghwCfg)
 	}
-	return Some(w)
+	return w
 }
 
 // tagJob creates the job that runs on the release-PR merge event; it reads the
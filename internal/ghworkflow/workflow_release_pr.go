@@ -4,20 +4,17 @@
 package ghworkflow
 
 import (
-	. "go.xyrillian.de/gg/option"
-
 	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
 // releasePRWorkflow renders .github/workflows/release-pr.yaml when the
 // release-PR automation is opted in. See README for details.
-func releasePRWorkflow(cfg core.Configuration) Option[workflow] {
+func releasePRWorkflow(cfg core.Configuration) workflow {
 	ghwCfg := cfg.GitHubWorkflow
 	w := newWorkflow("release-pr", ghwCfg.Global.DefaultBranch, nil)
 
-	enabled := ghwCfg.Release.Enabled.UnwrapOr(cfg.GoReleaser.ShouldCreateConfig()) && ghwCfg.Release.ReleasePR.UnwrapOr(true)
-	if w.deleteUnless(enabled) {
-		return None[workflow]()
+	if w.disableUnless(ghwCfg.Release.IsReleasePREnabled(cfg.GoReleaser)) {
+		return w
 	}
 
 	w.Permissions.Contents = tokenScopeWrite
@@ -109,7 +106,7 @@ func releasePRWorkflow(cfg core.Configuration) Option[workflow] {
 
 	w.Jobs = map[string]job{"draft-release": j}
 
-	return Some(w)
+	return w
 }
 
 const releasePRBody = `Automated release PR for v${{ steps.bump.outputs.version }}.
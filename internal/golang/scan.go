@@ -4,6 +4,7 @@
 package golang
 
 import (
+	"maps"
 	"os"
 	"slices"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"github.com/sapcc/go-bits/must"
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/semver"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
 )
 
 // ScanResult contains data obtained through a scan of the configuration files
@@ -27,6 +30,44 @@ type ScanResult struct {
 	KubernetesController bool              // whether the repository contains a Kubernetes controller
 	KubernetesVersion    string            // version of kubernetes to use, derived from k8s.io/api
 	ModuleReplacements   map[string]string // key = replaced module path, value = replacing module path
+	Modules              []string          // from "require" directives in go.mod, e.g. "github.com/lib/pq"
+	Tools                []string          // from "tool" directives in go.mod, e.g. "golang.org/x/tools/cmd/goimports"
+}
+
+// ToolPackages maps the names of the helper tools used in the generated files
+// to the package paths that they are built from.
+var ToolPackages = map[string]string{
+	"addlicense":          "github.com/google/addlicense",
+	"controller-gen":      "sigs.k8s.io/controller-tools/cmd/controller-gen",
+	"go-licence-detector": "go.elastic.co/go-licence-detector",
+	"goimports":           "golang.org/x/tools/cmd/goimports",
+	"golangci-lint":       "github.com/golangci/golangci-lint/v2/cmd/golangci-lint",
+	"setup-envtest":       "sigs.k8s.io/controller-runtime/tools/setup-envtest",
+}
+
+// HasGoTool returns whether the given helper tool (a key in ToolPackages) is declared
+// by a "tool" directive in go.mod, i.e. whether it can be run with `go tool`.
+func (sr ScanResult) HasGoTool(name string) bool {
+	return slices.Contains(sr.Tools, ToolPackages[name])
+}
+
+// ToolCommand returns the command for running the given helper tool (a key in ToolPackages).
+func (sr ScanResult) ToolCommand(name string) string {
+	if sr.HasGoTool(name) {
+		return "go tool " + name
+	}
+	return name
+}
+
+// TestServices returns the services that the unit tests need to have installed locally (as keys of core.TestServices).
+func (sr ScanResult) TestServices() []string {
+	var result []string
+	for _, name := range slices.Sorted(maps.Keys(core.TestServices)) {
+		if slices.ContainsFunc(core.TestServices[name].Modules, func(m string) bool { return slices.Contains(sr.Modules, m) }) {
+			result = append(result, name)
+		}
+	}
+	return result
 }
 
 const ModFilename = "go.mod"
@@ -48,8 +89,8 @@ func Scan() ScanResult {
 		hasBinInfo           bool
 		kubernetesController bool
 		kubernetesVersion    string
+		modules              []string
 		useGinkgo            bool
-		usesPostgres         bool
 	)
 
 	for _, v := range modFile.Require {
@@ -58,9 +99,7 @@ func Scan() ScanResult {
 				hasBinInfo = true
 			}
 		}
-		if slices.Contains([]string{"github.com/lib/pq", "github.com/jackc/pgx/v5"}, v.Mod.Path) {
-			usesPostgres = true
-		}
+		modules = append(modules, v.Mod.Path)
 		if !v.Indirect && strings.HasPrefix(v.Mod.Path, "github.com/onsi/ginkgo") {
 			useGinkgo = true
 		}
@@ -81,20 +120,28 @@ func Scan() ScanResult {
 		goVersion = strings.Join(goVersionSlice[:len(goVersionSlice)-1], ".")
 	}
 
+	var tools []string
+	for _, t := range modFile.Tool {
+		tools = append(tools, t.Path)
+	}
+
 	moduleReplacements := make(map[string]string)
 	for _, r := range modFile.Replace {
 		moduleReplacements[r.Old.Path] = r.New.Path
 	}
 
-	return ScanResult{
+	result := ScanResult{
 		GoVersion:            modFile.Go.Version,
 		GoVersionMajorMinor:  goVersion,
 		ModulePath:           modFile.Module.Mod.Path,
 		HasBinInfo:           hasBinInfo,
 		UseGinkgo:            useGinkgo,
-		UsesPostgres:         usesPostgres,
 		KubernetesController: kubernetesController,
 		KubernetesVersion:    kubernetesVersion,
 		ModuleReplacements:   moduleReplacements,
+		Modules:              modules,
+		Tools:                tools,
 	}
+	result.UsesPostgres = slices.Contains(result.TestServices(), "postgres")
+	return result
 }
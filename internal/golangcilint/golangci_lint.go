@@ -13,6 +13,7 @@ import (
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
@@ -22,6 +23,29 @@ var (
 	configTemplate string
 )
 
+// Generator renders the golangci-lint configuration.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "golangci-lint"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.GolangciLint.CreateConfig
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config, ctx.ScanResult)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".golangci.yaml"}
+}
+
 // RenderConfig writes the golanci-lint configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 	must.Succeed(util.WriteFileFromTemplate(".golangci.yaml", configTemplate, map[string]any{
@@ -34,7 +58,7 @@ func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 		"ReviveRules":       cfg.GolangciLint.ReviveRules,
 		"SkipDirs":          cfg.GolangciLint.SkipDirs,
 		"Timeout":           cmp.Or(cfg.GolangciLint.Timeout, 5*time.Minute), // default to 5m0s
-		"WithControllerGen": cfg.ControllerGen.Enabled.UnwrapOr(sr.KubernetesController),
+		"WithControllerGen": cfg.ControllerGen.IsEnabled(sr.KubernetesController),
 		// liquid-ceph has an insane vendoring setup that we tried to replace with Go workspaces,
 		// but after getting stuck on bizarre module lookup errors, we decided to grandfather this in for now
 		"AllowReplaceLocal": sr.ModulePath == "github.com/cobaltcore-dev/liquid-ceph",
@@ -6,9 +6,12 @@ package goreleaser
 import (
 	_ "embed"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 
 	"github.com/sapcc/go-bits/logg"
@@ -23,6 +26,29 @@ var (
 	goreleaserTemplate string
 )
 
+// Generator renders the GoReleaser configuration and the release documentation.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "goreleaser"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.ShouldRenderGoReleaserConfig()
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".goreleaser.yaml", "RELEASE.md"}
+}
+
 // RenderConfig writes the goreleaser configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration) {
 	if len(cfg.Binaries) < 1 {
@@ -32,11 +58,6 @@ func RenderConfig(cfg core.Configuration) {
 		logg.Fatal("GoReleaser requires metadata.url to be configured!")
 	}
 
-	nameTemplate := `{{ .ProjectName }}-{{ replace .Version "v" "" }}-{{ .Os }}-{{ .Arch }}`
-	if cfg.GoReleaser.NameTemplate != "" {
-		nameTemplate = cfg.GoReleaser.NameTemplate
-	}
-
 	if cfg.GoReleaser.Files == nil {
 		cfg.GoReleaser.Files = &[]string{
 			"CHANGELOG.md",
@@ -45,16 +66,31 @@ func RenderConfig(cfg core.Configuration) {
 		}
 	}
 
-	binary := cfg.Binaries[0]
+	// each binary gets its own build entry, unless goReleaser.binaryName selects a single one:
+	// either one of the binaries by name, or the first binary that is then released under the given name
+	var builds []build
 	for _, bin := range cfg.Binaries {
-		if bin.Name == cfg.GoReleaser.BinaryName {
-			binary = bin
-			break
-		}
+		builds = append(builds, newBuild(bin, cfg.Golang))
 	}
-	binaryName := binary.Name
 	if cfg.GoReleaser.BinaryName != "" {
-		binaryName = cfg.GoReleaser.BinaryName
+		idx := slices.IndexFunc(builds, func(b build) bool { return b.BinName == cfg.GoReleaser.BinaryName })
+		builds = builds[max(idx, 0) : max(idx, 0)+1]
+		builds[0].BinaryName = cfg.GoReleaser.BinaryName
+	}
+	if len(builds) > 1 {
+		// build IDs must be unique when there is more than one build
+		for idx := range builds {
+			builds[idx].ID = builds[idx].BinName
+		}
+	}
+
+	nameTemplate := `{{ .ProjectName }}-{{ replace .Version "v" "" }}-{{ .Os }}-{{ .Arch }}`
+	if cfg.GoReleaser.Format == "binary" && len(builds) > 1 {
+		// each binary is uploaded on its own, so their names must differ
+		nameTemplate = `{{ .Binary }}-{{ replace .Version "v" "" }}-{{ .Os }}-{{ .Arch }}`
+	}
+	if cfg.GoReleaser.NameTemplate != "" {
+		nameTemplate = cfg.GoReleaser.NameTemplate
 	}
 
 	var (
@@ -75,18 +111,15 @@ func RenderConfig(cfg core.Configuration) {
 
 	releasePR := false
 	if cfg.GitHubWorkflow != nil {
-		releaseEnabled := cfg.GitHubWorkflow.Release.Enabled.UnwrapOr(cfg.GoReleaser.ShouldCreateConfig())
-		releasePR = releaseEnabled && cfg.GitHubWorkflow.Release.ReleasePR.UnwrapOr(true)
+		releasePR = cfg.GitHubWorkflow.Release.IsReleasePREnabled(cfg.GoReleaser)
 	}
 
 	must.Succeed(util.WriteFileFromTemplate(".goreleaser.yaml", goreleaserTemplate, map[string]any{
 		"nameTemplate": nameTemplate,
 		"format":       cfg.GoReleaser.Format,
 		"files":        cfg.GoReleaser.Files,
-		"binaryName":   binaryName,
-		"binName":      binary.Name,
+		"builds":       builds,
 		"ldflags":      cfg.Golang.LdFlags,
-		"fromPackage":  binary.FromPackage,
 		"githubDomain": metadataURL,
 	}))
 	must.Succeed(util.WriteFileFromTemplate("RELEASE.md", releaseMDTemplate, map[string]any{
@@ -94,3 +127,39 @@ func RenderConfig(cfg core.Configuration) {
 		"releasePR": releasePR,
 	}))
 }
+
+// build contains the settings for one entry in the builds section of the goreleaser configuration.
+type build struct {
+	ID          string
+	BinaryName  string
+	BinName     string
+	FromPackage string
+	CGO         bool
+	Env         map[string]string
+	BuildFlags  []string
+	BuildTags   []string
+	LdFlags     []string
+}
+
+func newBuild(bin core.BinaryConfiguration, golangCfg core.GolangConfiguration) build {
+	var buildFlags []string
+	if golangCfg.Reproducible {
+		buildFlags = append(buildFlags, core.ReproducibleBuildFlags...)
+	}
+	for _, flag := range bin.BuildFlags {
+		if !slices.Contains(buildFlags, flag) {
+			buildFlags = append(buildFlags, flag)
+		}
+	}
+
+	return build{
+		BinaryName:  bin.Name,
+		BinName:     bin.Name,
+		FromPackage: bin.FromPackage,
+		CGO:         bin.CGO.UnwrapOr(false),
+		Env:         bin.Env,
+		BuildFlags:  buildFlags,
+		BuildTags:   bin.BuildTags,
+		LdFlags:     bin.LdFlags,
+	}
+}
@@ -6,12 +6,13 @@ package hyperspace
 import (
 	"bytes"
 	"encoding/json"
-	"os"
 	"strings"
 
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
@@ -49,10 +50,37 @@ var defaultConfig = config{
 	},
 }
 
+// Generator renders the Hyperspace pull request bot configuration.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "hyperspace"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return isInternalRepo(cfg)
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".hyperspace/pull_request_bot.json"}
+}
+
+// isInternalRepo returns whether the repository is hosted on an internal GitHub instance where the Hyperspace bot is available.
+func isInternalRepo(cfg core.Configuration) bool {
+	return strings.HasPrefix(cfg.Metadata.URL, "https://github.wdf.sap.corp") || strings.HasPrefix(cfg.Metadata.URL, "https://github.tools.sap")
+}
+
 // RenderConfig writes the renovate configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration) {
-	isInternalRepo := strings.HasPrefix(cfg.Metadata.URL, "https://github.wdf.sap.corp") || strings.HasPrefix(cfg.Metadata.URL, "https://github.tools.sap")
-	if !isInternalRepo {
+	if !isInternalRepo(cfg) {
 		return
 	}
 
@@ -62,6 +90,5 @@ func RenderConfig(cfg core.Configuration) {
 	encoder.SetEscapeHTML(false)
 	must.Succeed(encoder.Encode(defaultConfig))
 
-	must.Succeed(os.MkdirAll(".hyperspace", 0750))
 	must.Succeed(util.WriteFile(".hyperspace/pull_request_bot.json", buf.Bytes()))
 }
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package makefile
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildHelper compiles one of the helper programs that are written into the testing/ directory of the target repository.
+func buildHelper(t *testing.T, fileName string) string {
+	t.Helper()
+	binaryPath := filepath.Join(t.TempDir(), strings.TrimSuffix(fileName, ".go"))
+	output, err := exec.Command("go", "build", "-o", binaryPath, fileName).CombinedOutput()
+	if err != nil {
+		t.Fatalf("could not build %s: %s\n%s", fileName, err.Error(), output)
+	}
+	return binaryPath
+}
+
+// runHelper runs a helper program that was compiled by buildHelper, and returns its stdout, stderr and exit code.
+func runHelper(t *testing.T, binaryPath, stdin string, args ...string) (stdout, stderr string, exitCode int) {
+	t.Helper()
+	var outBuf, errBuf strings.Builder
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		t.Fatalf("could not run %s: %s", binaryPath, err.Error())
+	}
+	return outBuf.String(), errBuf.String(), exitCode
+}
+
+func writeTestFile(t *testing.T, fileName, contents string) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), fileName)
+	err := os.WriteFile(filePath, []byte(contents), 0o666)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return filePath
+}
+
+// coverageProfile has the following coverage per package:
+//
+//	example.com/foo                  3/4 statements (75%)
+//	example.com/foo/internal/db      1/4 statements (25%)
+//	example.com/foo/internal/dbutil  1/2 statements (50%)
+//	example.com/foo/internal/empty   0/0 statements (ignored)
+const coverageProfile = `mode: set
+example.com/foo/main.go:10.2,12.3 3 1
+example.com/foo/main.go:14.2,14.10 1 0
+example.com/foo/internal/db/db.go:5.2,6.3 1 2
+example.com/foo/internal/db/db.go:8.2,10.3 3 0
+
+example.com/foo/internal/dbutil/util.go:5.2,6.3 1 1
+example.com/foo/internal/dbutil/util.go:8.2,10.3 1 0
+example.com/foo/internal/empty/empty.go:3.14,3.16 0 0
+`
+
+func TestCheckCoverage(t *testing.T) {
+	binaryPath := buildHelper(t, "check-coverage.go")
+	profilePath := writeTestFile(t, "cover.out", coverageProfile)
+
+	testCases := []struct {
+		Name             string
+		Args             []string
+		ExpectedExitCode int
+		ExpectedOutput   string
+	}{
+		{
+			Name:             "no minimums",
+			Args:             nil,
+			ExpectedExitCode: 0,
+			ExpectedOutput:   "All packages meet their minimum test coverage.\n",
+		},
+		{
+			Name:             "global minimum",
+			Args:             []string{"-minimum", "50"},
+			ExpectedExitCode: 1,
+			ExpectedOutput: "The following packages are below their minimum test coverage:\n\n" +
+				"| Package | Coverage | Minimum |\n| --- | ---: | ---: |\n" +
+				"| example.com/foo/internal/db | 25.0% | 50.0% |\n",
+		},
+		{
+			Name: "package minimums override the global minimum",
+			Args: []string{"-minimum", "80", "-package", "/internal/db=20"},
+			// example.com/foo falls back to -minimum, but both internal/db and internal/dbutil match the regex
+			ExpectedExitCode: 1,
+			ExpectedOutput: "The following packages are below their minimum test coverage:\n\n" +
+				"| Package | Coverage | Minimum |\n| --- | ---: | ---: |\n" +
+				"| example.com/foo | 75.0% | 80.0% |\n",
+		},
+		{
+			Name: "the highest of multiple matching package minimums applies",
+			Args: []string{"-package", "/internal/=10", "-package", "/internal/db=30", "-package", "/internal/dbutil$=60"},
+			// internal/db needs 30%, internal/dbutil needs 60%
+			ExpectedExitCode: 1,
+			ExpectedOutput: "The following packages are below their minimum test coverage:\n\n" +
+				"| Package | Coverage | Minimum |\n| --- | ---: | ---: |\n" +
+				"| example.com/foo/internal/db | 25.0% | 30.0% |\n" +
+				"| example.com/foo/internal/dbutil | 50.0% | 60.0% |\n",
+		},
+		{
+			Name: "packages without statements are ignored",
+			Args: []string{"-minimum", "10", "-package", "/internal/empty$=100"},
+			// internal/empty has no statements, so it cannot fail
+			ExpectedExitCode: 0,
+			ExpectedOutput:   "All packages meet their minimum test coverage.\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			summaryPath := filepath.Join(t.TempDir(), "summary.md")
+			t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+
+			stdout, stderr, exitCode := runHelper(t, binaryPath, "", append(tc.Args, profilePath)...)
+			if exitCode != tc.ExpectedExitCode {
+				t.Errorf("expected exit code %d, but got %d (stderr: %q)", tc.ExpectedExitCode, exitCode, stderr)
+			}
+			if stdout != tc.ExpectedOutput {
+				t.Errorf("expected output %q, but got %q", tc.ExpectedOutput, stdout)
+			}
+			summary, err := os.ReadFile(summaryPath)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if string(summary) != "## Test coverage\n\n"+tc.ExpectedOutput {
+				t.Errorf("expected step summary %q, but got %q", "## Test coverage\n\n"+tc.ExpectedOutput, string(summary))
+			}
+		})
+	}
+}
+
+func TestCheckCoverageRejectsMalformedProfiles(t *testing.T) {
+	binaryPath := buildHelper(t, "check-coverage.go")
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	for _, line := range []string{
+		"example.com/foo/main.go:10.2,12.3 3",
+		"example.com/foo/main.go:10.2,12.3 three 1",
+		"example.com/foo/main.go:10.2,12.3 3 once",
+		"example.com/foo/main.go 3 1",
+	} {
+		profilePath := writeTestFile(t, "cover.out", "mode: set\n"+line+"\n")
+		stdout, stderr, exitCode := runHelper(t, binaryPath, "", "-minimum", "0", profilePath)
+		expected := "malformed line in " + profilePath + ": "
+		if exitCode != 1 || stdout != "" || !strings.Contains(stderr, expected) {
+			t.Errorf("expected %q to fail with %q, but got exit code %d, stdout %q and stderr %q", line, expected, exitCode, stdout, stderr)
+		}
+	}
+
+	_, stderr, exitCode := runHelper(t, binaryPath, "", "-package", "/internal/db", "cover.out")
+	if exitCode != 2 || !strings.Contains(stderr, "expected REGEX=PERCENT") {
+		t.Errorf("expected -package without percentage to be rejected, but got exit code %d and stderr %q", exitCode, stderr)
+	}
+}
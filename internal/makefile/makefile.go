@@ -4,8 +4,6 @@
 package makefile
 
 import (
-	_ "embed"
-	"encoding/json"
 	"fmt"
 	"maps"
 	"path"
@@ -15,27 +13,17 @@ import (
 	"strings"
 
 	"github.com/sapcc/go-bits/logg"
-	"github.com/sapcc/go-bits/must"
+	"golang.org/x/mod/semver"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
-	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
-//go:embed editorconfig
-var editorconfig []byte
-
-//go:embed license-scan-rules.json
-var licenseRules []byte
-
-//go:embed license-scan-overrides.jsonl.tmpl
-var scanOverrides string
-
 // newMakefile defines the structure of the Makefile. Order is important as categories,
 // rules, and definitions will appear in the exact order as they are defined.
 func newMakefile(cfg core.Configuration, sr golang.ScanResult) *makefile {
 	hasBinaries := len(cfg.Binaries) > 0
-	runControllerGen := cfg.ControllerGen.Enabled.UnwrapOr(sr.KubernetesController)
+	runControllerGen := cfg.ControllerGen.IsEnabled(sr.KubernetesController)
 	// TODO: checking on GoVersion is only an aid until we can properly detect rust applications
 	isGolang := sr.GoVersion != ""
 
@@ -44,8 +32,7 @@ func newMakefile(cfg core.Configuration, sr golang.ScanResult) *makefile {
 		logg.Error("Some defaults or usages of the metadata might not work correctly")
 	}
 
-	isSAPCC := cfg.Metadata.IsSAPProject()
-	reuseEnabled := cfg.Reuse.Enabled.UnwrapOr(true)
+	reuseEnabled := cfg.Reuse.IsEnabled()
 
 	///////////////////////////////////////////////////////////////////////////
 	// General
@@ -91,57 +78,96 @@ endif
 	// Prepare
 	prepare := category{name: "prepare"}
 
-	installTool := func(name, modulePath string) []string {
-		return []string{
-			fmt.Sprintf(`@if ! hash %s 2>/dev/null; then`, name) +
-				fmt.Sprintf(` printf "\e[1;36m>> Installing %s (this may take a while)...\e[0m\n";`, name) +
-				fmt.Sprintf(` go install %s; fi`, modulePath),
+	// With makefile.localTools, each tool is installed into its own directory below build/tools,
+	// and all of these directories are put in front of $PATH, so that each project can use its own tool versions.
+	var localToolDirs []string
+	// toolInstallation returns the parts of an install-* recipe that depend on makefile.localTools:
+	// the condition for installing the tool, the local directory to install it into (or "" for the global default),
+	// and the version check for when the tool is already installed.
+	toolInstallation := func(name, version, versionCmd string) (condition, localDir, versionCheck string) {
+		if !cfg.Makefile.LocalTools {
+			return fmt.Sprintf(` if ! hash %s 2>/dev/null; then`, name), "", checkToolVersion(name, version, versionCmd)
 		}
+		localDir = fmt.Sprintf("$(CURDIR)/build/tools/%s-%s", name, version)
+		localToolDirs = append(localToolDirs, localDir)
+		// since the directory is specific to the version, the version does not need to be checked
+		return fmt.Sprintf(` if [ ! -x "%s/%s" ]; then`, localDir, name), localDir, ""
 	}
 
-	var prepareStaticRecipe []string
-	if isGolang {
-		prepare.addRule(rule{
-			description: "Install goimports required by goimports/static-check",
-			phony:       true,
-			target:      "install-goimports",
-			recipe:      installTool("goimports", "golang.org/x/tools/cmd/goimports@latest"),
-		})
+	// Go tools that are declared in go.mod are run with `go tool` and do not need to be installed.
+	// installPrerequisites returns the prerequisites for targets that run the given tool.
+	installPrerequisites := func(name string) []string {
+		if sr.HasGoTool(name) {
+			return nil
+		}
+		return []string{"install-" + name}
+	}
+	// addInstallToolRule adds the install-* target for the given tool (if needed) and returns installPrerequisites(name).
+	addInstallToolRule := func(name, version, description string) []string {
+		if sr.HasGoTool(name) {
+			return nil
+		}
+		condition, localDir, versionCheck := toolInstallation(name, version,
+			fmt.Sprintf(`go version -m "$$(command -v %s)" | awk '$$1 == "mod" { print $$3 }'`, name))
+		goInstall := "go install"
+		if localDir != "" {
+			goInstall = fmt.Sprintf(`GOBIN="%s" go install`, localDir)
+		}
 		prepare.addRule(rule{
-			description: "Install golangci-lint required by run-golangci-lint/static-check",
+			description: description,
 			phony:       true,
-			target:      "install-golangci-lint",
-			recipe:      installTool("golangci-lint", "github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest"),
+			target:      "install-" + name,
+			recipe: []string{
+				"@" + strings.TrimPrefix(condition, " ") +
+					fmt.Sprintf(` printf "\e[1;36m>> Installing %s %s (this may take a while)...\e[0m\n";`, name, version) +
+					fmt.Sprintf(` %s %s@%s;`, goInstall, golang.ToolPackages[name], version) +
+					versionCheck +
+					` fi`,
+			},
 		})
-		prepareStaticRecipe = append(prepareStaticRecipe, "install-goimports", "install-golangci-lint")
+		return installPrerequisites(name)
+	}
+
+	var prepareStaticRecipe []string
+	if isGolang {
+		prepareStaticRecipe = append(prepareStaticRecipe, addInstallToolRule("goimports", cfg.Tools.GetGoimportsVersion(),
+			"Install goimports required by goimports/static-check")...)
+		prepareStaticRecipe = append(prepareStaticRecipe, addInstallToolRule("golangci-lint", cfg.Tools.GetGolangciLintVersion(),
+			"Install golangci-lint required by run-golangci-lint/static-check")...)
 	}
 
 	if cfg.ShellCheck.IsEnabled() {
+		shellcheckVersion := cfg.Tools.GetShellcheckVersion()
+		condition, localDir, versionCheck := toolInstallation("shellcheck", shellcheckVersion, `shellcheck --version | awk '$$1 == "version:" { print "v" $$2 }'`)
 		prepare.addRule(rule{
 			description: "Install shellcheck required by run-shellcheck/static-check",
 			phony:       true,
 			target:      "install-shellcheck",
 			recipe: []string{
 				`@set -eou pipefail; ` +
-					` if ! hash shellcheck 2>/dev/null; then` +
-					` printf "\e[1;36m>> Installing shellcheck...\e[0m\n";` +
+					condition +
+					fmt.Sprintf(` printf "\e[1;36m>> Installing shellcheck %s...\e[0m\n";`, shellcheckVersion) +
 					` SHELLCHECK_ARCH=$$(uname -m);` +
 					// relevant for MacOS
 					` if [[ "$$SHELLCHECK_ARCH" == "arm64" ]]; then SHELLCHECK_ARCH=aarch64; fi;` +
 					` SHELLCHECK_OS=$$(uname -s | tr '[:upper:]' '[:lower:]');` +
-					` SHELLCHECK_VERSION="stable";` +
+					fmt.Sprintf(` SHELLCHECK_VERSION=%s;`, shellcheckVersion) +
 					` if command -v curl >/dev/null 2>&1; then GET="curl -sLo-"; elif command -v wget >/dev/null 2>&1; then GET="wget -O-"; else echo "Didn't find curl or wget to download shellcheck"; exit 2; fi;` +
-					` $$GET "https://github.com/koalaman/shellcheck/releases/download/$$SHELLCHECK_VERSION/shellcheck-$$SHELLCHECK_VERSION.$$SHELLCHECK_OS.$$SHELLCHECK_ARCH.tar.xz" | tar -Jxf -;` +
-					// hardcoding go here is not nice but since we mainly target go it should be acceptable
-					` BIN=$$(go env GOBIN); if [[ -z $$BIN ]]; then BIN=$$(go env GOPATH)/bin; fi;` +
+					downloadVerifiedArchive("shellcheck", shellcheckVersion, "$$SHELLCHECK_OS/$$SHELLCHECK_ARCH", core.KnownToolChecksums()["shellcheck"][shellcheckVersion]) +
+					` tar -Jxf "$$ARCHIVE"; rm -f "$$ARCHIVE";` +
+					setInstallDir(localDir) +
 					` install -Dm755 shellcheck-$$SHELLCHECK_VERSION/shellcheck -t "$$BIN";` +
-					` rm -rf shellcheck-$$SHELLCHECK_VERSION; fi`,
+					` rm -rf shellcheck-$$SHELLCHECK_VERSION;` +
+					versionCheck +
+					` fi`,
 			},
 		})
 		prepareStaticRecipe = append(prepareStaticRecipe, "install-shellcheck")
 	}
 
 	if cfg.Typos.IsEnabled() {
+		typosVersion := cfg.Tools.GetTyposVersion()
+		condition, localDir, versionCheck := toolInstallation("typos", typosVersion, `typos --version | awk '{ print "v" $$2 }'`)
 		prepare.addRule(rule{
 			description: "Install typos required by run-typos/static-check",
 			phony:       true,
@@ -149,43 +175,33 @@ endif
 			recipe: []string{
 				// see https://github.com/crate-ci/typos/blob/master/action/entrypoint.sh
 				`@set -eou pipefail; ` +
-					` if ! hash typos 2>/dev/null; then` +
-					` printf "\e[1;36m>> Installing typos...\e[0m\n";` +
+					condition +
+					fmt.Sprintf(` printf "\e[1;36m>> Installing typos %s...\e[0m\n";`, typosVersion) +
 					` TYPOS_ARCH=$$(uname -m);` +
 					// relevant for MacOS
 					` if [[ "$$TYPOS_ARCH" == "arm64" ]]; then TYPOS_ARCH=aarch64; fi;` +
+					` TYPOS_OS=$$(uname -s | tr '[:upper:]' '[:lower:]');` +
 					` if command -v curl >/dev/null 2>&1; then GET="curl $${GITHUB_TOKEN:+" -u \":$$GITHUB_TOKEN\""} -sLo-"; elif command -v wget >/dev/null 2>&1; then GET="wget $${GITHUB_TOKEN:+" --password \"$$GITHUB_TOKEN\""} -O-"; else echo "Didn't find curl or wget to download typos"; exit 2; fi;` +
-					` if command -v gh >/dev/null; then TYPOS_GET_RELEASE_JSON="gh api /repos/crate-ci/typos/releases"; else TYPOS_GET_RELEASE_JSON="$$GET https://api.github.com/repos/crate-ci/typos/releases"; fi;` +
-					` TYPOS_VERSION=$$($$TYPOS_GET_RELEASE_JSON | jq -r '.[0].name' );` +
-					` if [[ $(UNAME_S) == Darwin ]]; then TYPOS_FILE="typos-$$TYPOS_VERSION-$$TYPOS_ARCH-apple-darwin.tar.gz"; elif [[ $(UNAME_S) == Linux ]]; then TYPOS_FILE="typos-$$TYPOS_VERSION-$$TYPOS_ARCH-unknown-linux-musl.tar.gz"; fi;` +
+					downloadVerifiedArchive("typos", typosVersion, "$$TYPOS_OS/$$TYPOS_ARCH", core.KnownToolChecksums()["typos"][typosVersion]) +
 					` mkdir -p typos;` +
-					` $$GET ""https://github.com/crate-ci/typos/releases/download/$$TYPOS_VERSION/$$TYPOS_FILE"" | tar -C typos -zxf -;` +
-					// hardcoding go here is not nice but since we mainly target go it should be acceptable
-					` BIN=$$(go env GOBIN); if [[ -z $$BIN ]]; then BIN=$$(go env GOPATH)/bin; fi;` +
+					` tar -C typos -zxf "$$ARCHIVE"; rm -f "$$ARCHIVE";` +
+					setInstallDir(localDir) +
 					` install -Dm755 typos/typos -t "$$BIN";` +
-					` rm -rf typos/; fi`,
+					` rm -rf typos/;` +
+					versionCheck +
+					` fi`,
 			},
 		})
 		prepareStaticRecipe = append(prepareStaticRecipe, "install-typos")
 	}
 
-	if isGolang && (cfg.License.AddHeaders.UnwrapOr(isSAPCC) || cfg.License.CheckDependencies.UnwrapOr(isSAPCC)) {
-		prepare.addRule(rule{
-			description: "Install-go-licence-detector required by check-dependency-licenses/static-check",
-			phony:       true,
-			target:      "install-go-licence-detector",
-			recipe:      installTool("go-licence-detector", "go.elastic.co/go-licence-detector@latest"),
-		})
-		prepareStaticRecipe = append(prepareStaticRecipe, "install-go-licence-detector")
+	if isGolang && (cfg.ShouldAddLicenseHeaders() || cfg.ShouldCheckLicenseDependencies()) {
+		prepareStaticRecipe = append(prepareStaticRecipe, addInstallToolRule("go-licence-detector", cfg.Tools.GetGoLicenceDetectorVersion(),
+			"Install-go-licence-detector required by check-dependency-licenses/static-check")...)
 	}
-	if cfg.License.AddHeaders.UnwrapOr(isSAPCC) {
-		prepare.addRule(rule{
-			description: "Install addlicense required by check-license-headers/license-headers/static-check",
-			phony:       true,
-			target:      "install-addlicense",
-			recipe:      installTool("addlicense", "github.com/google/addlicense@latest"),
-		})
-		prepareStaticRecipe = append(prepareStaticRecipe, "install-addlicense")
+	if cfg.ShouldAddLicenseHeaders() {
+		prepareStaticRecipe = append(prepareStaticRecipe, addInstallToolRule("addlicense", cfg.Tools.GetAddlicenseVersion(),
+			"Install addlicense required by check-license-headers/license-headers/static-check")...)
 
 		if reuseEnabled {
 			prepare.addRule(rule{
@@ -217,30 +233,23 @@ endif
 	})
 
 	if runControllerGen {
-		prepare.addRule(rule{
-			description: "Install controller-gen required by static-check and build-all. This is used in CI before dropping privileges, you should probably install all the tools using your package manager",
-			phony:       true,
-			target:      "install-controller-gen",
-			recipe:      installTool("controller-gen", "sigs.k8s.io/controller-tools/cmd/controller-gen@latest"),
-		})
+		addInstallToolRule("controller-gen", cfg.Tools.GetControllerGenVersion(),
+			"Install controller-gen required by static-check and build-all. This is used in CI before dropping privileges, you should probably install all the tools using your package manager")
+		addInstallToolRule("setup-envtest", cfg.Tools.GetSetupEnvtestVersion(),
+			"Install setup-envtest required by check. This is used in CI before dropping privileges, you should probably install all the tools using your package manager")
+	}
 
-		prepare.addRule(rule{
-			description: "Install setup-envtest required by check. This is used in CI before dropping privileges, you should probably install all the tools using your package manager",
-			phony:       true,
-			target:      "install-setup-envtest",
-			recipe:      installTool("setup-envtest", "sigs.k8s.io/controller-runtime/tools/setup-envtest@latest"),
-		})
+	if len(localToolDirs) > 0 {
+		prepare.addDefinition("# tools are installed into build/tools/ instead of into GOBIN (see makefile.localTools in Makefile.maker.yaml)")
+		prepare.addDefinition("export PATH := %s:$(PATH)", strings.Join(localToolDirs, ":"))
 	}
 
 	///////////////////////////////////////////////////////////////////////////
 	// Build
 	build := category{name: "build"}
 
-	var defaultBuildFlags, defaultLdFlags string
-
-	if cfg.Golang.EnableVendoring {
-		defaultBuildFlags = "-mod vendor"
-	}
+	defaultBuildFlags := cfg.Golang.DefaultBuildFlags()
+	var defaultLdFlags string
 
 	if len(cfg.Golang.LdFlags) > 0 {
 		var names []string
@@ -272,7 +281,31 @@ endif
 		build.addDefinition("# no .git directory is present or to provide a fixed build date for reproducibility.")
 		build.addDefinition(`BININFO_VERSION     ?= $(shell git describe --tags --always --abbrev=7)`)
 		build.addDefinition(`BININFO_COMMIT_HASH ?= $(shell git rev-parse --verify HEAD)`)
-		build.addDefinition(`BININFO_BUILD_DATE  ?= $(shell date -u +"%Y-%m-%dT%H:%M:%SZ")`)
+		if cfg.Golang.Reproducible {
+			build.addDefinition("# For reproducible builds (see golang.reproducible in Makefile.maker.yaml), the build date is not the current time, but SOURCE_DATE_EPOCH or the commit timestamp.")
+			build.addDefinition(strings.TrimSpace(`
+ifdef SOURCE_DATE_EPOCH
+BININFO_BUILD_DATE  ?= $(shell date -u -d "@$(SOURCE_DATE_EPOCH)" +"%Y-%m-%dT%H:%M:%SZ" 2>/dev/null || date -u -r "$(SOURCE_DATE_EPOCH)" +"%Y-%m-%dT%H:%M:%SZ")
+else
+BININFO_BUILD_DATE  ?= $(shell TZ=UTC git log -1 --format=%cd --date=format-local:"%Y-%m-%dT%H:%M:%SZ")
+endif
+`))
+		} else {
+			build.addDefinition(`BININFO_BUILD_DATE  ?= $(shell date -u +"%Y-%m-%dT%H:%M:%SZ")`)
+		}
+	}
+	if isGolang {
+		build.addDefinition("")
+		build.addDefinition(`# to get around weird Makefile syntax restrictions, we need variables containing nothing, a space and comma`)
+		build.addDefinition(`null :=`)
+		build.addDefinition(`space := $(null) $(null)`)
+		build.addDefinition(`comma := ,`)
+		build.addDefinition("")
+		build.addDefinition("# All source files (including those for other platforms, and the directories to notice deleted files) of the given packages and of the packages from this module that they import.")
+		build.addDefinition("# This is used as prerequisites for the build/ targets, so that they are only rebuilt when something has changed. Use `make -B` to force a rebuild.")
+		build.addDefinition("# Since make expands prerequisites while reading the Makefile, this runs `go list` once per binary on every invocation of make, even for targets like `make help`.")
+		build.addDefinition(`go_sources = $(wildcard go.mod go.sum vendor/modules.txt) $(shell env $(GO_BUILDENV) go list $(GO_BUILDFLAGS) -deps -f '%s' $(1))`,
+			goSourcesTemplate(false))
 	}
 
 	handledVariables := []string{"GO_BUILDFLAGS", "GO_LDFLAGS", "GO_TESTFLAGS", "GO_TESTENV", "GO_BUILDENV"}
@@ -290,10 +323,13 @@ endif
 	}
 
 	if hasBinaries {
-		build.addRule(buildTargets(cfg.Binaries, sr, runControllerGen)...)
+		build.addRule(buildTargets(cfg.Binaries, cfg.Golang, sr, runControllerGen)...)
 		if r, ok := installTarget(cfg.Binaries, &cfg); ok {
 			build.addRule(r)
 		}
+		if cfg.Golang.Reproducible {
+			build.addRule(verifyReproducibleTarget(cfg.Binaries))
+		}
 	}
 
 	///////////////////////////////////////////////////////////////////////////
@@ -321,6 +357,22 @@ GO_TESTPKGS := ./...
 endif
 `))
 
+		if cfg.Test.Integration.Enabled {
+			test.addDefinition(`# which packages to test with the integration test runner`)
+			integrationPkgGreps := ""
+			if cfg.Test.Integration.Only != "" {
+				integrationPkgGreps += fmt.Sprintf(" | grep -E '%s'", strings.ReplaceAll(cfg.Test.Integration.Only, "$", "$$"))
+			}
+			if cfg.Test.Integration.Except != "" {
+				integrationPkgGreps += fmt.Sprintf(" | grep -Ev '%s'", strings.ReplaceAll(cfg.Test.Integration.Except, "$", "$$"))
+			}
+			test.addDefinition(`GO_INTEGRATION_TESTPKGS := $(shell go list -tags %s -f '{{if or .TestGoFiles .XTestGoFiles}}{{.%s}}{{end}}' ./...%s)`,
+				strings.Join(cfg.Test.Integration.GetTags(), ","), pathVar, integrationPkgGreps)
+			test.addDefinition("# build tags for the integration tests (since only the last -tags flag takes effect, this includes the tags given as `-tags=...` in GO_BUILDFLAGS)")
+			test.addDefinition(`GO_INTEGRATION_TAGS = $(subst $(space),$(comma),$(strip $(subst $(comma),$(space),$(patsubst -tags=%%,%%,$(filter -tags=%%,$(GO_BUILDFLAGS)))) %s))`,
+				strings.Join(cfg.Test.Integration.GetTags(), " "))
+		}
+
 		test.addDefinition(`# which packages to measure coverage for`)
 		coverPkgGreps := ""
 		if cfg.Coverage.Only != "" {
@@ -332,14 +384,12 @@ endif
 		test.addDefinition(`GO_COVERPKGS := $(shell go list ./...%s)`, coverPkgGreps)
 	}
 
-	test.addDefinition(`# to get around weird Makefile syntax restrictions, we need variables containing nothing, a space and comma`)
-	test.addDefinition(`null :=`)
-	test.addDefinition(`space := $(null) $(null)`)
-	test.addDefinition(`comma := ,`)
-
 	if isGolang {
 		// add main testing target
 		checkPrerequisites := []string{"static-check", "build/cover.html"}
+		if cfg.Coverage.HasMinimums() {
+			checkPrerequisites = append(checkPrerequisites, "check-coverage")
+		}
 		if hasBinaries {
 			checkPrerequisites = append(checkPrerequisites, "build-all")
 		}
@@ -366,6 +416,7 @@ endif
 			if cfg.ControllerGen.RBACRoleName != "" {
 				roleName = cfg.ControllerGen.RBACRoleName
 			}
+			controllerGen := sr.ToolCommand("controller-gen")
 			objectParams := ""
 			if cfg.ControllerGen.ObjectHeaderFile != "" {
 				objectParams = fmt.Sprintf(`:headerFile="%s",year=$(YEAR)`, cfg.ControllerGen.ObjectHeaderFile)
@@ -383,11 +434,11 @@ endif
 				target:      "generate",
 				recipe: []string{
 					`@printf "\e[1;36m>> controller-gen\e[0m\n"`,
-					fmt.Sprintf(`@controller-gen crd%s rbac:roleName=%s webhook paths="./..." output:crd:artifacts:config=%s output:rbac:artifacts:config=%s`, allowDangerousTypes, roleName, crdOutputPath, rbacOutputPath),
-					fmt.Sprintf(`@controller-gen object%s paths="./..."`, objectParams),
-					fmt.Sprintf(`@controller-gen applyconfiguration%s paths="./..."`, applyconfigurationParams),
+					fmt.Sprintf(`@%s crd%s rbac:roleName=%s webhook paths="./..." output:crd:artifacts:config=%s output:rbac:artifacts:config=%s`, controllerGen, allowDangerousTypes, roleName, crdOutputPath, rbacOutputPath),
+					fmt.Sprintf(`@%s object%s paths="./..."`, controllerGen, objectParams),
+					fmt.Sprintf(`@%s applyconfiguration%s paths="./..."`, controllerGen, applyconfigurationParams),
 				},
-				prerequisites: []string{"install-controller-gen"},
+				prerequisites: installPrerequisites("controller-gen"),
 			})
 		}
 
@@ -396,11 +447,11 @@ endif
 			description:   "Install and run golangci-lint. Installing is used in CI, but you should probably install golangci-lint using your package manager.",
 			phony:         true,
 			target:        "run-golangci-lint",
-			prerequisites: []string{"install-golangci-lint"},
+			prerequisites: installPrerequisites("golangci-lint"),
 			recipe: []string{
 				`@printf "\e[1;36m>> golangci-lint\e[0m\n"`,
-				`@golangci-lint config verify`,
-				`@golangci-lint run`,
+				fmt.Sprintf(`@%s config verify`, sr.ToolCommand("golangci-lint")),
+				fmt.Sprintf(`@%s run`, sr.ToolCommand("golangci-lint")),
 			},
 		})
 
@@ -408,10 +459,6 @@ endif
 			// add target to run shellcheck
 			var ignorePathArgs strings.Builder
 			for _, path := range cfg.ShellCheck.AllIgnorePaths(cfg.Golang) {
-				if strings.HasPrefix(path, "/") {
-					logg.Fatal("ShellCheck ignore paths must not start with a slash, got: %s", path)
-				}
-
 				// https://github.com/ludeeus/action-shellcheck/blob/master/action.yaml#L120-L124
 				if !strings.HasPrefix(path, "./") {
 					fmt.Fprintf(&ignorePathArgs, " \\( -path '*/%s/*' -prune \\) -o", path)
@@ -445,10 +492,14 @@ endif
 		}
 
 		// add targets for test runner incl. coverage report
+		test.addDefinition("# tests are rerun when any source file (including test files) or any file in a testdata/ directory has changed")
+		test.addDefinition("# (like go_sources, this is computed on every invocation of make)")
+		test.addDefinition(`GO_TEST_SOURCES := $(wildcard go.mod go.sum vendor/modules.txt) $(shell env $(GO_BUILDENV) go list $(GO_BUILDFLAGS) -deps -f '%s' ./...) $(shell find . \( -path ./vendor -o -path ./build \) -prune -o -type f -path '*/testdata/*' -print)`,
+			goSourcesTemplate(true))
 		testRule := rule{
-			description: "Run tests and generate coverage report.",
-			phony:       true,
-			target:      "build/cover.out",
+			description:   "Run tests and generate coverage report.",
+			target:        "build/cover.out",
+			prerequisites: []string{"$(GO_TEST_SOURCES)"},
 			// We use order only prerequisite because this target is used in CI.
 			orderOnlyPrerequisites: []string{"build"},
 			recipe: []string{
@@ -468,20 +519,68 @@ endif
 			singleThreaded = "-p 1 "
 		}
 
+		// goTest returns the command line for running the test suite with `go test` or Ginkgo, respectively.
+		// The runnerFlags are given to the test runner, the coverageFlags go after the linker flags, and testPkgs selects the packages to test.
+		// goTest returns the command that runs the given test packages;
+		// runnerFlags are placed before $(GO_BUILDFLAGS), extraFlags after it (so that they take precedence)
+		goTest := func(runnerFlags, extraFlags, testPkgs string) string {
+			testRunner := "go test -shuffle=on " + singleThreaded
+			if sr.UseGinkgo {
+				testRunner = "go run github.com/onsi/ginkgo/v2/ginkgo run --randomize-all "
+			}
+			if runnerFlags != "" {
+				testRunner += runnerFlags + " "
+			}
+			return fmt.Sprintf(`%s$(GO_BUILDFLAGS) -ldflags '%s $(GO_LDFLAGS)'%s $(GO_TESTFLAGS) %s`,
+				testRunner, makeDefaultLinkerFlags(path.Base(sr.ModulePath), sr), extraFlags, testPkgs)
+		}
+		// testRecipe returns the recipe line that runs the given test command in the test environment,
+		// optionally piping its output into another command
+		testRecipe := func(cmd, pipeTo string) string {
+			line := `env $(GO_TESTENV) ` + cmd
+			if runControllerGen {
+				line = fmt.Sprintf(`KUBEBUILDER_ASSETS=$$(%s use %s -p path) %s`, sr.ToolCommand("setup-envtest"), sr.KubernetesVersion, cmd)
+			}
+			if pipeTo != "" {
+				line = "set -eo pipefail; " + line + " | " + pipeTo
+			}
+			if !runControllerGen {
+				line = "@" + line
+			}
+			return line
+		}
+		var testPrerequisites []string
+		if runControllerGen {
+			testPrerequisites = append([]string{"generate"}, installPrerequisites("setup-envtest")...)
+		}
+
 		// NOTE: Ginkgo will always write the coverage profile as "coverprofile.out", so we will choose the same path for non-Ginkgo tests, too.
 		// The actual final path is build/cover.out, which will be filled by a post-processing step below.
-		testRunner := fmt.Sprintf("go test -shuffle=on %s-coverprofile=build/coverprofile.out", singleThreaded)
+		coverageRunnerFlags := "-coverprofile=build/coverprofile.out"
 		if sr.UseGinkgo {
-			testRunner = "go run github.com/onsi/ginkgo/v2/ginkgo run --randomize-all -output-dir=build"
+			coverageRunnerFlags = "-output-dir=build"
 		}
-		goTest := fmt.Sprintf(`%s $(GO_BUILDFLAGS) -ldflags '%s $(GO_LDFLAGS)' -covermode=count -coverpkg=$(subst $(space),$(comma),$(GO_COVERPKGS)) $(GO_TESTFLAGS) $(GO_TESTPKGS)`,
-			testRunner, makeDefaultLinkerFlags(path.Base(sr.ModulePath), sr))
-		if runControllerGen {
-			testRule.prerequisites = append(testRule.prerequisites, "generate", "install-setup-envtest")
-			testRule.recipe = append(testRule.recipe, fmt.Sprintf(`KUBEBUILDER_ASSETS=$$(setup-envtest use %s -p path) %s`, sr.KubernetesVersion, goTest))
-		} else {
-			testRule.recipe = append(testRule.recipe, `@env $(GO_TESTENV) `+goTest)
+		// Ginkgo writes the test reports by itself, otherwise the output of `go test -json` is converted by a helper program
+		testReportCmd := ""
+		if sr.UseGinkgo {
+			if cfg.Test.HasReport("junit") {
+				coverageRunnerFlags += " --junit-report=test-report.xml"
+			}
+			if cfg.Test.HasReport("json") {
+				coverageRunnerFlags += " --json-report=test-report.json"
+			}
+		} else if len(cfg.Test.Reports) > 0 {
+			coverageRunnerFlags = "-json " + coverageRunnerFlags
+			testReportCmd = "go run " + testReportFile
+			if cfg.Test.HasReport("junit") {
+				testReportCmd += " -junit build/test-report.xml"
+			}
+			if cfg.Test.HasReport("json") {
+				testReportCmd += " -json build/test-report.json"
+			}
 		}
+		testRule.prerequisites = append(testRule.prerequisites, testPrerequisites...)
+		testRule.recipe = append(testRule.recipe, testRecipe(goTest(coverageRunnerFlags, ` -covermode=count -coverpkg=$(subst $(space),$(comma),$(GO_COVERPKGS))`, "$(GO_TESTPKGS)"), testReportCmd))
 		// workaround for <https://github.com/fgrosse/go-coverage-report/issues/61>: merge block coverage manually
 		testRule.recipe = append(testRule.recipe, `@awk < build/coverprofile.out '$$1 != "mode:" { is_filename[$$1] = true; counts1[$$1]+=$$2; counts2[$$1]+=$$3 } END { for (filename in is_filename) { printf "%s %d %d\n", filename, counts1[filename], counts2[filename]; } }' | sort | $(SED) '1s/^/mode: count\n/' > $@`)
 
@@ -496,6 +595,114 @@ endif
 				`@go tool cover -html $< -o $@`,
 			},
 		})
+
+		if cfg.Coverage.HasMinimums() {
+			checkCoverageCmd := "@go run " + checkCoverageFile
+			if cfg.Coverage.Minimum > 0 {
+				checkCoverageCmd += fmt.Sprintf(" -minimum %g", cfg.Coverage.Minimum)
+			}
+			for _, rx := range slices.Sorted(maps.Keys(cfg.Coverage.PackageMinimums)) {
+				checkCoverageCmd += fmt.Sprintf(" -package '%s=%g'", strings.ReplaceAll(rx, "$", "$$"), cfg.Coverage.PackageMinimums[rx])
+			}
+			test.addRule(rule{
+				description:   "Check that each package reaches its minimum test coverage.",
+				phony:         true,
+				target:        "check-coverage",
+				prerequisites: []string{"build/cover.out"},
+				recipe: []string{
+					`@printf "\e[1;36m>> Checking test coverage\e[0m\n"`,
+					checkCoverageCmd + " build/cover.out",
+				},
+			})
+		}
+
+		// test variants do not produce a coverage report since the race detector and `-short` change which code is covered
+		raceTest := goTest("-race", "", "$(GO_TESTPKGS)")
+		shortTest := goTest("-short", "", "$(GO_TESTPKGS)")
+		if sr.UseGinkgo {
+			raceTest = goTest("--race", "", "$(GO_TESTPKGS)")
+			// Ginkgo does not understand -short, but passes everything after `--` on to the test binaries
+			shortTest = goTest("", "", "$(GO_TESTPKGS)") + " -- -test.short"
+		}
+		test.addRule(rule{
+			description:   "Run the test suite with the race detector enabled.",
+			phony:         true,
+			target:        "check-race",
+			prerequisites: testPrerequisites,
+			recipe: []string{
+				`@printf "\e[1;36m>> Running tests with race detector\e[0m\n"`,
+				testRecipe(raceTest, ""),
+			},
+		})
+		test.addRule(rule{
+			description:   "Run the test suite in short mode, i.e. skip tests that check testing.Short().",
+			phony:         true,
+			target:        "check-short",
+			prerequisites: testPrerequisites,
+			recipe: []string{
+				`@printf "\e[1;36m>> Running tests in short mode\e[0m\n"`,
+				testRecipe(shortTest, ""),
+			},
+		})
+
+		if cfg.Test.Integration.Enabled {
+			integrationFlags := " -tags=$(GO_INTEGRATION_TAGS)"
+			if sr.UseGinkgo {
+				integrationFlags = " --tags=$(GO_INTEGRATION_TAGS)"
+			}
+			integrationTest := goTest("", integrationFlags, "$(GO_INTEGRATION_TESTPKGS)")
+			if env := cfg.Test.Integration.Env; len(env) > 0 {
+				var assignments []string
+				for _, key := range slices.Sorted(maps.Keys(env)) {
+					assignments = append(assignments, fmt.Sprintf("%s='%s'", key, strings.ReplaceAll(env[key], "$", "$$")))
+				}
+				integrationTest = strings.Join(assignments, " ") + " " + integrationTest
+			}
+			test.addRule(rule{
+				description:   "Run the integration tests. The services that they need must be running already.",
+				phony:         true,
+				target:        "check-integration",
+				prerequisites: testPrerequisites,
+				recipe: []string{
+					`@printf "\e[1;36m>> Running integration tests\e[0m\n"`,
+					fmt.Sprintf(`@if [ -z '$(strip $(GO_INTEGRATION_TESTPKGS))' ]; then printf "\e[1;31m>> No packages with integration tests found (build tags: %s)\e[0m\n"; exit 1; fi`,
+						strings.Join(cfg.Test.Integration.GetTags(), ",")),
+					testRecipe(integrationTest, ""),
+				},
+			})
+		}
+
+		// fuzz targets are discovered when running `make fuzz`, so that the Makefile does not need to be regenerated when they change
+		fuzzCmd := `env $(GO_TESTENV) go test $(GO_BUILDFLAGS) -run '^$$' -fuzz "^$$func\$$" -fuzztime $(FUZZTIME) "$$pkg"`
+		test.addDefinition("# how long `make fuzz` runs each fuzz target (see `go help testflag` for the format)")
+		test.addDefinition("FUZZTIME ?= %s", cfg.Test.Fuzz.GetTime())
+		listFuzzTargets := `@set -eo pipefail; env $(GO_TESTENV) go test $(GO_BUILDFLAGS) -list '^Fuzz' $(GO_TESTPKGS) | awk '/^Fuzz/ { funcs[n++] = $$1; next } $$1 == "ok" { for (i = 0; i < n; i++) print $$2 "." funcs[i]; n = 0 }'`
+		if cfg.Test.Fuzz.Except != "" {
+			listFuzzTargets += fmt.Sprintf(" | { grep -Ev '%s' || true; }", strings.ReplaceAll(cfg.Test.Fuzz.Except, "$", "$$"))
+		}
+		test.addRule(rule{
+			description: "Run each fuzz test for $(FUZZTIME). Run a single one with e.g. 'make fuzz-internal-parser-FuzzParse'.",
+			phony:       true,
+			target:      "fuzz",
+			recipe: []string{
+				listFuzzTargets + ` | while read -r target; do` +
+					` pkg="$${target%.*}"; func="$${target##*.}";` +
+					` printf "\e[1;36m>> Fuzzing %s in %s\e[0m\n" "$$func" "$$pkg";` +
+					` ` + fuzzCmd + ` || exit 1;` +
+					` done`,
+			},
+		})
+		test.addRule(rule{
+			phony:  true,
+			target: "fuzz-%",
+			recipe: []string{
+				// e.g. `make fuzz-internal-parser-FuzzParse` for the package in internal/parser, or `make fuzz-FuzzParse` for the package in the repository root
+				// (slashes cannot be used here since make only matches the last path element against the pattern)
+				`@set -eo pipefail; target='$*'; func="$${target##*-}"; slug="$${target%-*}"; if [ "$$slug" = "$$target" ]; then slug=; fi; ` +
+					`pkg="$$(go list $(GO_BUILDFLAGS) -f '{{.ImportPath}} {{.Module.Path}}' ./... | awk -v slug="$$slug" '{ rel = substr($$1, length($$2) + 2); gsub("/", "-", rel); if (rel == slug) print $$1 }')"; ` +
+					`if [ -z "$$pkg" ]; then printf "\e[1;31m>> No package found for %s\e[0m\n" "$*"; exit 1; fi; ` + fuzzCmd,
+			},
+		})
 	}
 
 	///////////////////////////////////////////////////////////////////////////
@@ -551,7 +758,7 @@ endif
 		allSourceFilesExpr = `$(shell find -name *.rs)`
 	}
 
-	if cfg.License.AddHeaders.UnwrapOr(isSAPCC) || cfg.Typos.IsEnabled() {
+	if cfg.ShouldAddLicenseHeaders() || cfg.Typos.IsEnabled() {
 		// Darwin's sed does not support sed -i but sed -i ""
 		// xargs fails: command line cannot be assembled, too long
 		general.addDefinition(strings.TrimSpace(`
@@ -565,7 +772,7 @@ endif
 `))
 	}
 
-	if cfg.License.AddHeaders.UnwrapOr(isSAPCC) {
+	if cfg.ShouldAddLicenseHeaders() {
 		var ignoreOptions []string
 		if cfg.GitHubWorkflow != nil {
 			for _, pattern := range cfg.GitHubWorkflow.License.IgnorePatterns {
@@ -575,7 +782,7 @@ endif
 		}
 		ignoreOptionsStr := strings.Join(append(ignoreOptions, "--"), " ")
 
-		licenseHeaderPrereqs := []string{"install-addlicense"}
+		licenseHeaderPrereqs := installPrerequisites("addlicense")
 		if reuseEnabled {
 			licenseHeaderPrereqs = append(licenseHeaderPrereqs, "install-reuse")
 		}
@@ -596,10 +803,10 @@ endif
 					// clean up old license headers
 					`gawk -i inplace '"'"'{if (display) {print} else {!/^\/\*/ && !/^\*/}}; {if (!display && $$0 ~ /^(package |$$)/) {display=1} else { }}'"'"' {}; `+
 					// Run addlicense tool, will be a no-op if the license header is already present
-					`addlicense -c "%s" -s=only -y "$$year" %s {}; `+
+					`%s -c "%s" -s=only -y "$$year" %s {}; `+
 					// Replace "// Copyright" with "// SPDX-FileCopyrightText:" to fulfill reuse
 					`$(SED) -i '"'"'1s+// Copyright +// SPDX-FileCopyrightText: +'"'"' {}; `+
-					`'`, allSourceFilesExpr, cfg.License.GetCopyright(), ignoreOptionsStr),
+					`'`, allSourceFilesExpr, sr.ToolCommand("addlicense"), cfg.License.GetCopyright(), ignoreOptionsStr),
 				`@printf "\e[1;36m>> reuse annotate (for license headers on other files)\e[0m\n"`,
 				fmt.Sprintf(`@reuse lint -j | jq -r '.non_compliant.missing_licensing_info[]' | sed '/\<vendor\>/d' | $(XARGS) reuse annotate -c '%s' -l %s --skip-unrecognised`, cfg.License.GetCopyright(), cfg.License.GetSPDX()),
 				`@printf "\e[1;36m>> reuse download --all\e[0m\n"`,
@@ -612,10 +819,10 @@ endif
 			description:   "Check license headers in all non-vendored .go files with addlicense.",
 			target:        "check-addlicense",
 			phony:         true,
-			prerequisites: []string{"install-addlicense"},
+			prerequisites: installPrerequisites("addlicense"),
 			recipe: []string{
 				`@printf "\e[1;36m>> addlicense --check\e[0m\n"`,
-				fmt.Sprintf(`@addlicense --check %s %s`, ignoreOptionsStr, allSourceFilesExpr),
+				fmt.Sprintf(`@%s --check %s %s`, sr.ToolCommand("addlicense"), ignoreOptionsStr, allSourceFilesExpr),
 			},
 		})
 		if reuseEnabled {
@@ -644,30 +851,15 @@ endif
 		})
 
 		if isGolang {
-			must.Succeed(util.WriteFile(".editorconfig", editorconfig))
-
-			licenseRulesFile := ".license-scan-rules.json"
-			must.Succeed(util.WriteFile(licenseRulesFile, licenseRules))
-
-			scanOverridesFile := ".license-scan-overrides.jsonl"
-			additionalOverridesFromCfg := cfg.License.GoLicenseDetector.Overrides
-			additionalOverrides := make([]string, len(additionalOverridesFromCfg))
-			for i, o := range additionalOverridesFromCfg {
-				additionalOverrides[i] = string(must.Return(json.Marshal(o)))
-			}
-			must.Succeed(util.WriteFileFromTemplate(scanOverridesFile, scanOverrides, map[string]any{
-				"AdditionalOverrides": additionalOverrides,
-			}))
-
 			dev.addRule(rule{
 				description:   "Check all dependency licenses using go-licence-detector.",
 				target:        "check-dependency-licenses",
 				phony:         true,
-				prerequisites: []string{"install-go-licence-detector"},
+				prerequisites: installPrerequisites("go-licence-detector"),
 				recipe: []string{
 					`@printf "\e[1;36m>> go-licence-detector\e[0m\n"`,
-					fmt.Sprintf(`@go list -m -mod=readonly -json all | go-licence-detector -includeIndirect -rules %s -overrides %s`,
-						licenseRulesFile, scanOverridesFile),
+					fmt.Sprintf(`@go list -m -mod=readonly -json all | %s -includeIndirect -rules %s -overrides %s`,
+						sr.ToolCommand("go-licence-detector"), licenseRulesFile, scanOverridesFile),
 				},
 			})
 		}
@@ -677,7 +869,7 @@ endif
 	if isGolang {
 		// add target for static code checks
 		staticCheckPrerequisites = append(staticCheckPrerequisites, "run-golangci-lint")
-		if cfg.License.AddHeaders.UnwrapOr(isSAPCC) {
+		if cfg.ShouldAddLicenseHeaders() {
 			staticCheckPrerequisites = append(staticCheckPrerequisites, "check-dependency-licenses")
 		}
 
@@ -685,15 +877,15 @@ endif
 			description:   "Run goimports on all non-vendored .go files",
 			phony:         true,
 			target:        "goimports",
-			prerequisites: []string{"install-goimports"},
+			prerequisites: installPrerequisites("goimports"),
 			recipe: []string{
 				fmt.Sprintf(`@printf "\e[1;36m>> goimports -w -local %s\e[0m\n"`, cfg.Metadata.URL),
-				fmt.Sprintf(`@goimports -w -local %s %s`, sr.ModulePath, allSourceFilesExpr),
+				fmt.Sprintf(`@%s -w -local %s %s`, sr.ToolCommand("goimports"), sr.ModulePath, allSourceFilesExpr),
 			},
 		})
 	}
 
-	if cfg.License.AddHeaders.UnwrapOr(isSAPCC) {
+	if cfg.ShouldAddLicenseHeaders() {
 		staticCheckPrerequisites = append(staticCheckPrerequisites, "check-license-headers")
 	}
 
@@ -712,14 +904,20 @@ endif
 	})
 
 	// add cleaning target
+	cleanRecipe := "git clean -dxf build"
+	if cfg.Makefile.LocalTools {
+		dev.addDefinition("# To also remove the tools in build/tools/, run `make clean CLEAN_TOOLS=1`.")
+		dev.addDefinition("CLEAN_TOOLS ?= 0")
+		cleanRecipe = "git clean -dxf $(if $(filter 1,$(CLEAN_TOOLS)),,-e /build/tools) build"
+	}
 	dev.addRule(rule{
 		description: "Run git clean.",
 		target:      "clean",
 		phony:       true,
-		recipe:      []string{"git clean -dxf build"},
+		recipe:      []string{cleanRecipe},
 	})
 
-	return &makefile{
+	m := &makefile{
 		categories: []category{
 			general,
 			prepare,
@@ -728,36 +926,71 @@ endif
 			dev,
 		},
 	}
+	m.addCustomTargets(cfg.Makefile.Targets)
+	m.applyOverrides(cfg.Makefile.Overrides)
+	return m
+}
+
+// applyOverrides changes the rules in this Makefile as described by the makefile.overrides config section.
+// Overrides for unknown or ambiguous targets are ignored here since they are reported by Generator.Validate().
+func (m *makefile) applyOverrides(overrides map[string]core.MakefileOverride) {
+	rules := m.rulesByTarget()
+	for name, o := range overrides {
+		if len(rules[name]) != 1 {
+			continue
+		}
+		r := rules[name][0]
+		r.prerequisites = slices.Concat(r.prerequisites, o.AddPrerequisites)
+		if recipe, ok := o.ReplaceRecipe.Unpack(); ok {
+			r.recipe = recipe
+		}
+		r.recipe = slices.Concat(o.PrependRecipe, r.recipe, o.AppendRecipe)
+		if o.Hide {
+			r.hideTarget = true
+		}
+	}
+}
+
+// addCustomTargets adds the targets from the makefile.targets config section to their respective categories.
+func (m *makefile) addCustomTargets(targets []core.MakefileTarget) {
+	for _, t := range targets {
+		for idx := range m.categories {
+			if m.categories[idx].name == t.GetCategory() {
+				m.categories[idx].addRule(rule{
+					description:            t.Description,
+					phony:                  t.IsPhony(),
+					target:                 t.Name,
+					recipe:                 t.Recipe,
+					prerequisites:          t.Prerequisites,
+					orderOnlyPrerequisites: t.OrderOnlyPrerequisites,
+					custom:                 true,
+				})
+			}
+		}
+	}
 }
 
-func buildTargets(binaries []core.BinaryConfiguration, sr golang.ScanResult, runControllerGen bool) []rule {
+func buildTargets(binaries []core.BinaryConfiguration, golangCfg core.GolangConfiguration, sr golang.ScanResult, runControllerGen bool) []rule {
+	platforms := golangCfg.Platforms
 	result := make([]rule, 0, len(binaries)+1)
 	buildAllRule := rule{
 		description: "Build all binaries.",
 		target:      "build-all",
 	}
-	if runControllerGen {
+	if runControllerGen && !sr.HasGoTool("controller-gen") {
 		buildAllRule.prerequisites = []string{"install-controller-gen"}
 	}
 	result = append(result, buildAllRule)
 
 	allPrerequisites := make([]string, 0, len(binaries))
 	for _, bin := range binaries {
-		r := rule{
-			description: fmt.Sprintf("Build %s.", bin.Name),
-			phony:       true,
-			target:      "build/" + bin.Name,
-			recipe: []string{fmt.Sprintf(
-				"env $(GO_BUILDENV) go build $(GO_BUILDFLAGS) -ldflags '%s $(GO_LDFLAGS)' -o build/%s %s",
-				makeDefaultLinkerFlags(bin.Name, sr),
-				bin.Name, bin.FromPackage,
-			)},
+		r := goBuildRule(bin, golangCfg, sr, "build/"+bin.Name, "", runControllerGen)
+		r.description = fmt.Sprintf("Build %s.", bin.Name)
+		if len(platforms) > 0 {
+			// the list of source files is shared with the cross-compilation targets, so only compute it once
+			r.addDefinition("go_sources_%s := %s", bin.Name, r.prerequisites[0])
+			r.prerequisites[0] = fmt.Sprintf("$(go_sources_%s)", bin.Name)
 		}
-
-		if runControllerGen {
-			r.prerequisites = append(r.prerequisites, "generate")
-		}
-
 		result = append(result, r)
 		allPrerequisites = append(allPrerequisites, r.target)
 
@@ -765,10 +998,10 @@ func buildTargets(binaries []core.BinaryConfiguration, sr golang.ScanResult, run
 		if filepath.Clean(bin.InstallTo) == "/opt/resource" {
 			for _, alias := range []string{"check", "in", "out"} {
 				r := rule{
-					description: fmt.Sprintf("Build %s.", alias),
-					phony:       true,
-					target:      "build/" + alias,
-					recipe:      []string{fmt.Sprintf("ln -sf %s build/%s", bin.Name, alias)},
+					description:   fmt.Sprintf("Build %s.", alias),
+					target:        "build/" + alias,
+					prerequisites: []string{"build/" + bin.Name},
+					recipe:        []string{fmt.Sprintf("ln -sf %s build/%s", bin.Name, alias)},
 				}
 				result = append(result, r)
 				allPrerequisites = append(allPrerequisites, r.target)
@@ -777,7 +1010,145 @@ func buildTargets(binaries []core.BinaryConfiguration, sr golang.ScanResult, run
 	}
 	result[0].prerequisites = allPrerequisites
 
-	return result
+	if len(platforms) == 0 {
+		return result
+	}
+
+	// cross-compilation for golang.platforms
+	allPlatformsRule := rule{
+		description: "Build all binaries for all platforms from golang.platforms.",
+		target:      "build-all-platforms",
+	}
+	distRule := rule{
+		description:   "Pack the binaries for all platforms from golang.platforms into archives in build/dist/.",
+		phony:         true,
+		target:        "dist",
+		prerequisites: []string{"build-all-platforms"},
+		recipe:        []string{"@rm -rf build/dist"},
+	}
+	var archives []string
+	for _, bin := range binaries {
+		for _, platform := range platforms {
+			goos, goarch, _ := strings.Cut(platform, "/")
+			name := fmt.Sprintf("%s-%s-%s", bin.Name, goos, goarch)
+			exeSuffix := ""
+			if goos == "windows" {
+				exeSuffix = ".exe"
+			}
+
+			r := goBuildRule(bin, golangCfg, sr, "build/"+name+exeSuffix, fmt.Sprintf(" GOOS=%s GOARCH=%s", goos, goarch), runControllerGen)
+			r.description = fmt.Sprintf("Build %s for %s.", bin.Name, platform)
+			r.prerequisites[0] = fmt.Sprintf("$(go_sources_%s)", bin.Name)
+			r.hideTarget = true
+			result = append(result, r)
+			allPlatformsRule.prerequisites = append(allPlatformsRule.prerequisites, r.target)
+
+			// the archive contains a directory with the binary under its usual name
+			distRule.addRecipe("@mkdir -p build/dist/%s", name)
+			distRule.addRecipe("cp %s build/dist/%s/%s%s", r.target, name, bin.Name, exeSuffix)
+			if goos == "windows" {
+				archives = append(archives, name+".zip")
+				distRule.addRecipe("cd build/dist && zip -qr %s.zip %s", name, name)
+			} else {
+				archives = append(archives, name+".tar.gz")
+				distRule.addRecipe("tar -czf build/dist/%s.tar.gz -C build/dist %s", name, name)
+			}
+			distRule.addRecipe("@rm -r build/dist/%s", name)
+		}
+	}
+	distRule.addRecipe(`@cd build/dist && if command -v sha256sum >/dev/null 2>&1; then sha256sum %[1]s; else shasum -a 256 %[1]s; fi > SHA256SUMS`, strings.Join(archives, " "))
+	distRule.addRecipe(`@printf "\e[1;32m>> Archives and SHA256SUMS written to build/dist/\e[0m\n"`)
+
+	return append(result, allPlatformsRule, distRule)
+}
+
+// verifyReproducibleTarget returns a rule that builds all binaries a second time
+// (with `-a` to ignore the build cache) and checks that the results are identical.
+func verifyReproducibleTarget(binaries []core.BinaryConfiguration) rule {
+	r := rule{
+		description:   "Check that building all binaries twice produces identical results.",
+		phony:         true,
+		target:        "verify-reproducible",
+		prerequisites: []string{"build-all"},
+		recipe:        []string{"@rm -rf build/reproducible && mkdir -p build/reproducible"},
+	}
+	for _, bin := range binaries {
+		r.addRecipe("@cp build/%[1]s build/reproducible/%[1]s", bin.Name)
+	}
+	r.addRecipe(`@printf "\e[1;36m>> Building all binaries a second time\e[0m\n"`)
+	r.addRecipe(`env GOFLAGS="$${GOFLAGS:+$$GOFLAGS }-a" $(MAKE) --no-print-directory -B build-all`)
+	for _, bin := range binaries {
+		r.addRecipe(`@if cmp -s build/%[1]s build/reproducible/%[1]s; then printf "\e[1;32m>> build/%[1]s is reproducible\e[0m\n"; else printf "\e[1;31m>> build/%[1]s differs between builds\e[0m\n"; exit 1; fi`, bin.Name)
+	}
+	return r
+}
+
+// goSourcesTemplate returns a template for `go list -f` that lists the directories and source files of all packages from this module.
+// Test files are only included if withTests is true, so that changes to tests do not cause the binaries to be rebuilt.
+func goSourcesTemplate(withTests bool) string {
+	fields := []string{"GoFiles", "CgoFiles", "EmbedFiles", "IgnoredGoFiles"}
+	if withTests {
+		fields = append(fields, "TestGoFiles", "XTestGoFiles", "TestEmbedFiles")
+	}
+	result := `{{if and .Module .Module.Main}}{{.Dir}}`
+	for _, field := range fields {
+		result += fmt.Sprintf(`{{range .%s}} {{$$.Dir}}/{{.}}{{end}}`, field)
+	}
+	return result + `{{end}}`
+}
+
+// goBuildRule returns a rule that builds the given binary into the given output path.
+// The extraEnv is appended to the environment for `go build` and must start with a space if not empty.
+func goBuildRule(bin core.BinaryConfiguration, golangCfg core.GolangConfiguration, sr golang.ScanResult, output, extraEnv string, runControllerGen bool) rule {
+	env, flags, ldflags := binaryBuildSettings(bin, golangCfg)
+	r := rule{
+		target: output,
+		// commas would be taken as argument separators by $(call)
+		prerequisites: []string{fmt.Sprintf("$(call go_sources,%s)",
+			strings.ReplaceAll(strings.TrimSpace(flags+" "+bin.FromPackage), ",", "$(comma)"),
+		)},
+		recipe: []string{fmt.Sprintf(
+			"env $(GO_BUILDENV)%s%s go build $(GO_BUILDFLAGS)%s -ldflags '%s $(GO_LDFLAGS)%s' -o %s %s",
+			env, extraEnv, flags, makeDefaultLinkerFlags(bin.Name, sr), ldflags,
+			output, bin.FromPackage,
+		)},
+	}
+
+	if runControllerGen {
+		r.prerequisites = append(r.prerequisites, "generate")
+	}
+	return r
+}
+
+// binaryBuildSettings renders the per-binary build settings from the given config into
+// environment variables, flags for `go build` and linker flags. Each non-empty result starts with a space.
+// Build flags that are already in GO_BUILDFLAGS because of golang.reproducible are skipped.
+func binaryBuildSettings(bin core.BinaryConfiguration, golangCfg core.GolangConfiguration) (env, flags, ldflags string) {
+	if cgo, ok := bin.CGO.Unpack(); ok {
+		if cgo {
+			env += " CGO_ENABLED=1"
+		} else {
+			env += " CGO_ENABLED=0"
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(bin.Env)) {
+		env += fmt.Sprintf(" %s=%s", key, bin.Env[key])
+	}
+
+	if len(bin.BuildTags) > 0 {
+		flags += " -tags " + strings.Join(bin.BuildTags, ",")
+	}
+	for _, flag := range bin.BuildFlags {
+		if golangCfg.Reproducible && slices.Contains(core.ReproducibleBuildFlags, flag) {
+			continue
+		}
+		flags += " " + flag
+	}
+
+	for _, flag := range bin.LdFlags {
+		ldflags += " " + flag
+	}
+	return env, flags, ldflags
 }
 
 func makeDefaultLinkerFlags(binaryName string, sr golang.ScanResult) string {
@@ -793,6 +1164,46 @@ func makeDefaultLinkerFlags(binaryName string, sr golang.ScanResult) string {
 	return flags
 }
 
+// downloadVerifiedArchive returns the part of an install-* recipe that downloads the release archive of the given tool
+// for the given platform (a shell expression) into "$$ARCHIVE" using "$$GET", and verifies it against the given checksums (keyed by platform).
+// If the checksum is known and does not match, the recipe fails. If the checksum is not known, the archive is used with a warning.
+func downloadVerifiedArchive(tool, version, platform string, checksums map[string]string) string {
+	result := fmt.Sprintf(` case "%s" in`, platform)
+	for _, p := range core.ToolPlatforms {
+		result += fmt.Sprintf(` %s) URL="%s"; SHA256="%s";;`, p, core.ToolReleaseURL(tool, version, p), checksums[p])
+	}
+	result += fmt.Sprintf(` *) echo "No release of %s is available for %s"; exit 2;;`, tool, platform) +
+		` esac;` +
+		` if command -v sha256sum >/dev/null 2>&1; then SHA256SUM=sha256sum; else SHA256SUM="shasum -a 256"; fi;` +
+		fmt.Sprintf(` ARCHIVE=%s-%s.download; $$GET "$$URL" > "$$ARCHIVE";`, tool, version) +
+		fmt.Sprintf(` if [[ -z "$$SHA256" ]]; then printf "\e[1;33m>> Warning: cannot verify the download of %s %s since go-makefile-maker does not know its checksum\e[0m\n";`, tool, version) +
+		fmt.Sprintf(` elif ! echo "$$SHA256  $$ARCHIVE" | $$SHA256SUM -c - >/dev/null 2>&1; then printf "\e[1;31m>> Checksum mismatch for $$URL, refusing to install %s\e[0m\n"; rm -f "$$ARCHIVE"; exit 1; fi;`, tool)
+	return result
+}
+
+// setInstallDir returns the part of an install-* recipe that sets $$BIN to the directory that a downloaded tool is installed into.
+// This is the given local directory for makefile.localTools, or the global GOBIN otherwise.
+func setInstallDir(localDir string) string {
+	if localDir != "" {
+		return fmt.Sprintf(` BIN="%s";`, localDir)
+	}
+	// hardcoding go here is not nice but since we mainly target go it should be acceptable
+	return ` BIN=$$(go env GOBIN); if [[ -z $$BIN ]]; then BIN=$$(go env GOPATH)/bin; fi;`
+}
+
+// checkToolVersion returns the part of an install-* recipe that runs when the tool is already installed.
+// It prints a warning if versionCmd reports a different version than the one that is configured.
+// Versions that are not semver (e.g. branch names) cannot be compared reliably, so no check is done for those.
+func checkToolVersion(name, version, versionCmd string) string {
+	if !semver.IsValid(version) {
+		return ""
+	}
+	return fmt.Sprintf(` else INSTALLED_VERSION=$$(%s);`, versionCmd) +
+		fmt.Sprintf(` if [[ "$$INSTALLED_VERSION" != "%s" ]]; then`, version) +
+		fmt.Sprintf(` printf "\e[1;33m>> Warning: %s $$INSTALLED_VERSION is installed, but %s expects %s\e[0m\n";`, name, core.ConfigurationPath, version) +
+		` fi;`
+}
+
 // installTarget also returns a bool that tells whether the install target was requested in the config.
 func installTarget(binaries []core.BinaryConfiguration, cfg *core.Configuration) (rule, bool) {
 	r := rule{
@@ -6,20 +6,117 @@ package makefile
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"maps"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
+//go:embed editorconfig
+var editorconfig []byte
+
+//go:embed license-scan-rules.json
+var licenseRules []byte
+
+//go:embed license-scan-overrides.jsonl.tmpl
+var scanOverrides string
+
+//go:embed check-coverage.go
+var checkCoverage []byte
+
+//go:embed test-report.go
+var testReport []byte
+
+const (
+	licenseRulesFile  = ".license-scan-rules.json"
+	scanOverridesFile = ".license-scan-overrides.jsonl"
+	checkCoverageFile = "testing/check-coverage.go"
+	testReportFile    = core.TestReportHelperPath
+)
+
+// Generator renders the Makefile and the configuration files for the tools invoked by it.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "makefile"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Makefile.IsEnabled()
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	Render(ctx.Config, ctx.ScanResult)
+}
+
+// Validate implements the generator.WithValidation interface.
+func (Generator) Validate(cfg core.Configuration, sr golang.ScanResult, report func(path, msg string, args ...any)) {
+	rules := newMakefile(cfg, sr).rulesByTarget()
+	isGenerated := func(r *rule) bool { return !r.custom }
+	for idx, t := range cfg.Makefile.Targets {
+		if slices.ContainsFunc(rules[t.Name], isGenerated) || slices.Contains(specialTargets, t.Name) {
+			report(fmt.Sprintf("makefile.targets[%d].name", idx), "makefile.targets[].name must not be the name of a generated target, but %q is generated already", t.Name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Makefile.Overrides)) {
+		switch len(rules[name]) {
+		case 0:
+			report("makefile.overrides."+name, "makefile.overrides refers to %q, but the Makefile does not have a target with that name", name)
+		case 1:
+			// OK
+		default:
+			report("makefile.overrides."+name, "makefile.overrides refers to %q, but the Makefile has %d rules for that target", name, len(rules[name]))
+		}
+	}
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(cfg core.Configuration, sr golang.ScanResult) []string {
+	result := []string{"Makefile"}
+	if rendersLicenseScanFiles(cfg, sr) {
+		result = append(result, ".editorconfig", licenseRulesFile, scanOverridesFile)
+	}
+	if rendersCheckCoverage(cfg, sr) {
+		result = append(result, checkCoverageFile)
+	}
+	if rendersTestReport(cfg, sr) {
+		result = append(result, testReportFile)
+	}
+	return result
+}
+
+// rendersCheckCoverage returns whether the Makefile has a check-coverage target that needs the helper program.
+func rendersCheckCoverage(cfg core.Configuration, sr golang.ScanResult) bool {
+	return sr.GoVersion != "" && cfg.Coverage.HasMinimums()
+}
+
+// rendersTestReport returns whether the test reports need the helper program,
+// either for converting the output of `go test -json` or for summarizing the JUnit report in the CI workflow.
+func rendersTestReport(cfg core.Configuration, sr golang.ScanResult) bool {
+	return sr.GoVersion != "" && len(cfg.Test.Reports) > 0
+}
+
+// rendersLicenseScanFiles returns whether the Makefile has license checks that need additional config files.
+func rendersLicenseScanFiles(cfg core.Configuration, sr golang.ScanResult) bool {
+	// TODO: checking on GoVersion is only an aid until we can properly detect rust applications
+	return sr.GoVersion != "" && cfg.ShouldAddLicenseHeaders()
+}
+
 // Render renders the Makefile.
 func Render(cfg core.Configuration, sr golang.ScanResult) {
 	var buf bytes.Buffer
@@ -59,12 +156,30 @@ func Render(cfg core.Configuration, sr golang.ScanResult) {
 
 	must.Succeed(util.WriteFile("Makefile", buf.Bytes()))
 
+	if rendersLicenseScanFiles(cfg, sr) {
+		must.Succeed(util.WriteFile(".editorconfig", editorconfig))
+		must.Succeed(util.WriteFile(licenseRulesFile, licenseRules))
+
+		additionalOverridesFromCfg := cfg.License.GoLicenseDetector.Overrides
+		additionalOverrides := make([]string, len(additionalOverridesFromCfg))
+		for i, o := range additionalOverridesFromCfg {
+			additionalOverrides[i] = string(must.Return(json.Marshal(o)))
+		}
+		must.Succeed(util.WriteFileFromTemplate(scanOverridesFile, scanOverrides, map[string]any{
+			"AdditionalOverrides": additionalOverrides,
+		}))
+	}
+
+	if rendersCheckCoverage(cfg, sr) {
+		must.Succeed(util.WriteFile(checkCoverageFile, checkCoverage))
+	}
+	if rendersTestReport(cfg, sr) {
+		must.Succeed(util.WriteFile(testReportFile, testReport))
+	}
+
 	if sr.UsesPostgres {
 		// Cleanup obsolete helper script that was previously managed by this tool.
-		err := os.Remove("testing/with-postgres-db.sh")
-		if !os.IsNotExist(err) {
-			must.Succeed(err)
-		}
+		must.Succeed(util.RemoveFile("testing/with-postgres-db.sh"))
 	}
 }
 
@@ -73,6 +188,21 @@ type makefile struct {
 	categories []category
 }
 
+// specialTargets are generated by Render() in addition to the rules from newMakefile().
+var specialTargets = []string{"vars", "help", "FORCE"}
+
+// rulesByTarget returns all rules in this Makefile, grouped by their target. The specialTargets are not included.
+func (m *makefile) rulesByTarget() map[string][]*rule {
+	result := make(map[string][]*rule)
+	for _, c := range m.categories {
+		for idx := range c.rules {
+			r := &c.rules[idx]
+			result[r.target] = append(result[r.target], r)
+		}
+	}
+	return result
+}
+
 func (m *makefile) vars() *rule {
 	// collect all variable refs that look like $(THIS) or $(LIKE_THAT) from definitions and recipes
 	varRefRx := regexp.MustCompile(`\$\([A-Za-z_][A-Za-z0-9_]*\)`)
@@ -96,6 +226,11 @@ func (m *makefile) vars() *rule {
 	delete(isVarRef, "$(comma)")
 	delete(isVarRef, "$(null)")
 	delete(isVarRef, "$(space)")
+	// this is an input from the environment (see golang.reproducible) that is usually not set
+	delete(isVarRef, "$(SOURCE_DATE_EPOCH)")
+	maps.DeleteFunc(isVarRef, func(varRef string, _ bool) bool {
+		return strings.HasPrefix(varRef, "$(go_sources_")
+	})
 
 	// compile a sorted list of variable names
 	var varNames []string
@@ -204,6 +339,7 @@ type rule struct {
 	target      string
 	hideTarget  bool // if true, the target will not be printed in the help output
 	recipe      []string
+	custom      bool // if true, the rule comes from the makefile.targets config section
 
 	// See https://www.gnu.org/software/make/manual/make.html#Prerequisite-Types.
 	prerequisites          []string
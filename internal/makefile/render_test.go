@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package makefile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+)
+
+func TestCustomTargetsMustNotShadowGeneratedTargets(t *testing.T) {
+	cfg := core.Configuration{
+		Binaries: []core.BinaryConfiguration{{Name: "example", FromPackage: "."}},
+		Makefile: core.MakefileConfig{
+			Targets: []core.MakefileTarget{
+				{Name: "generate"},
+				{Name: "build/example"},
+				{Name: "help"},
+			},
+		},
+	}
+	sr := golang.ScanResult{ModulePath: "example.com/example", GoVersion: "1.26.0"}
+
+	var problems []string
+	Generator{}.Validate(cfg, sr, func(path, msg string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(msg, args...))
+	})
+	expected := []string{
+		`makefile.targets[1].name: makefile.targets[].name must not be the name of a generated target, but "build/example" is generated already`,
+		`makefile.targets[2].name: makefile.targets[].name must not be the name of a generated target, but "help" is generated already`,
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %v, but got %v", expected, problems)
+	}
+	for idx := range expected {
+		if problems[idx] != expected[idx] {
+			t.Errorf("expected %q, but got %q", expected[idx], problems[idx])
+		}
+	}
+}
+
+func TestOverridesMustReferToExistingTargets(t *testing.T) {
+	cfg := core.Configuration{
+		Makefile: core.MakefileConfig{
+			Targets: []core.MakefileTarget{{Name: "generate"}},
+			Overrides: map[string]core.MakefileOverride{
+				"check":    {AddPrerequisites: []string{"generate"}},
+				"generate": {Hide: true},
+				"help":     {Hide: true},
+				"chekc":    {Hide: true},
+			},
+		},
+	}
+	sr := golang.ScanResult{ModulePath: "example.com/example", GoVersion: "1.26.0"}
+
+	var paths []string
+	Generator{}.Validate(cfg, sr, func(path, _ string, _ ...any) {
+		paths = append(paths, path)
+	})
+	expected := []string{"makefile.overrides.chekc", "makefile.overrides.help"}
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Errorf("expected problems for %v, but got %v", expected, paths)
+	}
+
+	// overrides are applied to both generated and custom targets
+	m := newMakefile(cfg, sr)
+	for _, c := range m.categories {
+		for _, r := range c.rules {
+			if r.target == "check" && r.prerequisites[len(r.prerequisites)-1] != "generate" {
+				t.Errorf("expected check to depend on generate, but got %v", r.prerequisites)
+			}
+			if r.target == "generate" && !r.hideTarget {
+				t.Error("expected generate to be hidden")
+			}
+		}
+	}
+}
+
+func TestOverridesMustNotBeAmbiguous(t *testing.T) {
+	cfg := core.Configuration{
+		Binaries: []core.BinaryConfiguration{{Name: "example", FromPackage: "."}},
+		Makefile: core.MakefileConfig{
+			Targets: []core.MakefileTarget{{Name: "build-all", Recipe: []string{"true"}}},
+			Overrides: map[string]core.MakefileOverride{
+				"build-all": {AppendRecipe: []string{"echo done"}},
+			},
+		},
+	}
+	sr := golang.ScanResult{ModulePath: "example.com/example", GoVersion: "1.26.0"}
+
+	var problems []string
+	Generator{}.Validate(cfg, sr, func(path, msg string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(msg, args...))
+	})
+	expected := []string{
+		`makefile.targets[0].name: makefile.targets[].name must not be the name of a generated target, but "build-all" is generated already`,
+		`makefile.overrides.build-all: makefile.overrides refers to "build-all", but the Makefile has 2 rules for that target`,
+	}
+	if !slices.Equal(problems, expected) {
+		t.Errorf("expected %q, but got %q", expected, problems)
+	}
+
+	// the ambiguous override is not applied to any of the rules
+	for _, r := range newMakefile(cfg, sr).rulesByTarget()["build-all"] {
+		if slices.Contains(r.recipe, "echo done") {
+			t.Errorf("expected override to not be applied, but got recipe %v", r.recipe)
+		}
+	}
+}
+
+func TestGoToolsAreNotInstalled(t *testing.T) {
+	cfg := core.Configuration{
+		GolangciLint: core.GolangciLintConfiguration{CreateConfig: true},
+	}
+	sr := golang.ScanResult{
+		ModulePath: "example.com/example",
+		GoVersion:  "1.26.0",
+		Tools:      []string{golang.ToolPackages["golangci-lint"]},
+	}
+
+	m := newMakefile(cfg, sr)
+	if rules := m.rulesByTarget(); rules["install-golangci-lint"] != nil || rules["install-goimports"] == nil {
+		t.Errorf("expected only install-goimports to be generated, but got %v", slices.Sorted(maps.Keys(rules)))
+	}
+	for _, c := range m.categories {
+		for _, r := range c.rules {
+			switch r.target {
+			case "prepare-static-check":
+				if slices.Contains(r.prerequisites, "install-golangci-lint") {
+					t.Errorf("expected prepare-static-check to not install golangci-lint, but got %v", r.prerequisites)
+				}
+			case "run-golangci-lint":
+				if len(r.prerequisites) > 0 || !strings.HasPrefix(r.recipe[len(r.recipe)-1], "@go tool golangci-lint run") {
+					t.Errorf("expected run-golangci-lint to use `go tool`, but got %v with prerequisites %v", r.recipe, r.prerequisites)
+				}
+			}
+		}
+	}
+}
+
+func TestDownloadVerifiedArchive(t *testing.T) {
+	recipe := downloadVerifiedArchive("typos", "v1.2.3", "$$OS/$$ARCH", map[string]string{"linux/x86_64": "0123abcd"})
+	expected := []string{
+		` linux/x86_64) URL="https://github.com/crate-ci/typos/releases/download/v1.2.3/typos-v1.2.3-x86_64-unknown-linux-musl.tar.gz"; SHA256="0123abcd";;`,
+		` darwin/aarch64) URL="https://github.com/crate-ci/typos/releases/download/v1.2.3/typos-v1.2.3-aarch64-apple-darwin.tar.gz"; SHA256="";;`,
+		`refusing to install typos`,
+		`Warning: cannot verify the download of typos v1.2.3`,
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(recipe, fragment) {
+			t.Errorf("expected recipe to contain %q, but got %q", fragment, recipe)
+		}
+	}
+}
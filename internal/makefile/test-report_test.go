@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package makefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// goTestJSON was recorded with `go test -json ./...` in a module with three packages:
+// "bad" has a passing and a failing test, "broken" does not compile, and "ok" has a passing test (with log output) and a skipped test.
+const goTestJSON = `{"Time":"2026-10-17T03:02:46.444470678Z","Action":"start","Package":"example.com/rec/bad"}
+{"Time":"2026-10-17T03:02:46.44702837Z","Action":"run","Package":"example.com/rec/bad","Test":"TestPass"}
+{"Time":"2026-10-17T03:02:46.447080912Z","Action":"output","Package":"example.com/rec/bad","Test":"TestPass","Output":"=== RUN   TestPass\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.447150674Z","Action":"output","Package":"example.com/rec/bad","Test":"TestPass","Output":"--- PASS: TestPass (0.00s)\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.44719142Z","Action":"pass","Package":"example.com/rec/bad","Test":"TestPass","Elapsed":0}
+{"Time":"2026-10-17T03:02:46.447217713Z","Action":"run","Package":"example.com/rec/bad","Test":"TestFail"}
+{"Time":"2026-10-17T03:02:46.447221107Z","Action":"output","Package":"example.com/rec/bad","Test":"TestFail","Output":"=== RUN   TestFail\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.447282194Z","Action":"output","Package":"example.com/rec/bad","Test":"TestFail","Output":"    bad_test.go:7: expected 1, got 2\n","OutputType":"error"}
+{"Time":"2026-10-17T03:02:46.447304468Z","Action":"output","Package":"example.com/rec/bad","Test":"TestFail","Output":"--- FAIL: TestFail (0.00s)\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.447337198Z","Action":"fail","Package":"example.com/rec/bad","Test":"TestFail","Elapsed":0}
+{"Time":"2026-10-17T03:02:46.447354356Z","Action":"output","Package":"example.com/rec/bad","Output":"FAIL\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.44769615Z","Action":"output","Package":"example.com/rec/bad","Output":"FAIL\texample.com/rec/bad\t0.003s\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.447706921Z","Action":"fail","Package":"example.com/rec/bad","Elapsed":0.003}
+{"ImportPath":"example.com/rec/broken [example.com/rec/broken.test]","Action":"build-output","Output":"# example.com/rec/broken [example.com/rec/broken.test]\n"}
+{"ImportPath":"example.com/rec/broken [example.com/rec/broken.test]","Action":"build-output","Output":"broken/broken_test.go:5:33: undefined: undefined\n"}
+{"ImportPath":"example.com/rec/broken [example.com/rec/broken.test]","Action":"build-fail"}
+{"Time":"2026-10-17T03:02:46.455780364Z","Action":"start","Package":"example.com/rec/broken"}
+{"Time":"2026-10-17T03:02:46.45579511Z","Action":"output","Package":"example.com/rec/broken","Output":"FAIL\texample.com/rec/broken [build failed]\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.455802881Z","Action":"fail","Package":"example.com/rec/broken","Elapsed":0,"FailedBuild":"example.com/rec/broken [example.com/rec/broken.test]"}
+{"Time":"2026-10-17T03:02:46.742830728Z","Action":"start","Package":"example.com/rec/ok"}
+{"Time":"2026-10-17T03:02:46.745024866Z","Action":"run","Package":"example.com/rec/ok","Test":"TestPass"}
+{"Time":"2026-10-17T03:02:46.745071716Z","Action":"output","Package":"example.com/rec/ok","Test":"TestPass","Output":"=== RUN   TestPass\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.745149876Z","Action":"output","Package":"example.com/rec/ok","Test":"TestPass","Output":"    ok_test.go:5: hello\n"}
+{"Time":"2026-10-17T03:02:46.745208418Z","Action":"output","Package":"example.com/rec/ok","Test":"TestPass","Output":"--- PASS: TestPass (0.00s)\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.745229136Z","Action":"pass","Package":"example.com/rec/ok","Test":"TestPass","Elapsed":0}
+{"Time":"2026-10-17T03:02:46.745269437Z","Action":"run","Package":"example.com/rec/ok","Test":"TestSkip"}
+{"Time":"2026-10-17T03:02:46.745273236Z","Action":"output","Package":"example.com/rec/ok","Test":"TestSkip","Output":"=== RUN   TestSkip\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.74530259Z","Action":"output","Package":"example.com/rec/ok","Test":"TestSkip","Output":"    ok_test.go:7: not today\n"}
+{"Time":"2026-10-17T03:02:46.745321978Z","Action":"output","Package":"example.com/rec/ok","Test":"TestSkip","Output":"--- SKIP: TestSkip (0.00s)\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.74562026Z","Action":"skip","Package":"example.com/rec/ok","Test":"TestSkip","Elapsed":0}
+{"Time":"2026-10-17T03:02:46.745635547Z","Action":"output","Package":"example.com/rec/ok","Output":"PASS\n","OutputType":"frame"}
+{"Time":"2026-10-17T03:02:46.745693763Z","Action":"output","Package":"example.com/rec/ok","Output":"ok  \texample.com/rec/ok\t0.003s\n"}
+{"Time":"2026-10-17T03:02:46.746036352Z","Action":"pass","Package":"example.com/rec/ok","Elapsed":0.003}
+`
+
+func TestTestReportConvert(t *testing.T) {
+	binaryPath := buildHelper(t, "test-report.go")
+	dir := t.TempDir()
+	junitPath := filepath.Join(dir, "test-report.xml")
+	jsonPath := filepath.Join(dir, "test-report.json")
+
+	stdout, stderr, exitCode := runHelper(t, binaryPath, goTestJSON, "-junit", junitPath, "-json", jsonPath)
+	if exitCode != 0 || stderr != "" {
+		t.Fatalf("expected success, but got exit code %d and stderr %q", exitCode, stderr)
+	}
+
+	// like `go test`, only failed tests and packages show their full output, and build errors are printed as-is
+	expectedStdout := "=== RUN   TestFail\n" +
+		"    bad_test.go:7: expected 1, got 2\n" +
+		"--- FAIL: TestFail (0.00s)\n" +
+		"FAIL\n" +
+		"FAIL\texample.com/rec/bad\t0.003s\n" +
+		"# example.com/rec/broken [example.com/rec/broken.test]\n" +
+		"broken/broken_test.go:5:33: undefined: undefined\n" +
+		"FAIL\texample.com/rec/broken [build failed]\n" +
+		"ok  \texample.com/rec/ok\t0.003s\n"
+	if stdout != expectedStdout {
+		t.Errorf("expected output %q, but got %q", expectedStdout, stdout)
+	}
+
+	// the JSON report is the original stream
+	assertFileContents(t, jsonPath, goTestJSON)
+
+	// the build errors are attributed to the package that could not be built
+	assertFileContents(t, junitPath, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="5" failures="2" skipped="1" time="0.006">
+  <testsuite name="example.com/rec/bad" tests="2" failures="1" skipped="0" time="0.003" timestamp="2026-10-17T03:02:46Z">
+    <testcase classname="example.com/rec/bad" name="TestPass" time="0"></testcase>
+    <testcase classname="example.com/rec/bad" name="TestFail" time="0">
+      <failure message="Failed">=== RUN   TestFail&#xA;    bad_test.go:7: expected 1, got 2&#xA;--- FAIL: TestFail (0.00s)&#xA;</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="example.com/rec/broken" tests="1" failures="1" skipped="0" time="0" timestamp="2026-10-17T03:02:46Z">
+    <testcase classname="example.com/rec/broken" name="(package)" time="0">
+      <failure message="Build failed"># example.com/rec/broken [example.com/rec/broken.test]&#xA;broken/broken_test.go:5:33: undefined: undefined&#xA;FAIL&#x9;example.com/rec/broken [build failed]&#xA;</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="example.com/rec/ok" tests="2" failures="0" skipped="1" time="0.003" timestamp="2026-10-17T03:02:46Z">
+    <testcase classname="example.com/rec/ok" name="TestPass" time="0"></testcase>
+    <testcase classname="example.com/rec/ok" name="TestSkip" time="0">
+      <skipped message="Skipped">=== RUN   TestSkip&#xA;    ok_test.go:7: not today&#xA;--- SKIP: TestSkip (0.00s)&#xA;</skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`)
+}
+
+func TestTestReportSummary(t *testing.T) {
+	binaryPath := buildHelper(t, "test-report.go")
+
+	// this report is in the format written by Ginkgo, which reports some failures as <error> instead of <failure>
+	junitPath := writeTestFile(t, "test-report.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="4" disabled="0" errors="1" failures="1" time="0.5">
+  <testsuite name="Example Suite" package="/src/example.com/foo/internal/api" tests="4" disabled="0" skipped="1" errors="1" failures="1" time="0.5">
+    <testcase name="[It] lists things" classname="Example Suite" status="passed" time="0.1"></testcase>
+    <testcase name="[It] creates things" classname="Example Suite" status="failed" time="0.1">
+      <failure message="Expected 1 to equal 2" type="failed">[FAILED] Expected 1 to equal 2</failure>
+    </testcase>
+    <testcase name="[It] deletes things" classname="Example Suite" status="panicked" time="0.1">
+      <error message="runtime error: invalid memory address" type="panicked">[PANICKED] runtime error</error>
+    </testcase>
+    <testcase name="[It] updates things" classname="Example Suite" status="skipped" time="0">
+      <skipped message="skipped"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`)
+	stdout, stderr, exitCode := runHelper(t, binaryPath, "", "-summary", junitPath)
+	expected := "**4 tests, 2 failed, 1 skipped**\n\n" +
+		"| Failed test | Package |\n| --- | --- |\n" +
+		"| `[It] creates things` | `Example Suite` |\n" +
+		"| `[It] deletes things` | `Example Suite` |\n"
+	if exitCode != 0 || stderr != "" || stdout != expected {
+		t.Errorf("expected summary %q, but got %q with exit code %d and stderr %q", expected, stdout, exitCode, stderr)
+	}
+
+	_, stderr, exitCode = runHelper(t, binaryPath, "", "-summary", writeTestFile(t, "broken.xml", "<testsuites"))
+	if exitCode != 1 || !strings.Contains(stderr, "test-report: cannot parse") {
+		t.Errorf("expected an unparseable report to fail, but got exit code %d and stderr %q", exitCode, stderr)
+	}
+}
+
+func assertFileContents(t *testing.T, filePath, expected string) {
+	t.Helper()
+	buf, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(buf) != expected {
+		t.Errorf("expected %s to contain %q, but got %q", filepath.Base(filePath), expected, string(buf))
+	}
+}
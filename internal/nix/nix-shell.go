@@ -9,10 +9,10 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
@@ -22,35 +22,69 @@ var (
 	shellNixTemplate string
 )
 
+// Generator renders the Nix shell.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "nix"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Nix.IsEnabled()
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderShell(ctx.Config, ctx.ScanResult, ctx.Config.ShouldRenderGoReleaserConfig())
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{"shell.nix"}
+}
+
 // RenderShell renders the Nix shell.
 func RenderShell(cfg core.Configuration, sr golang.ScanResult, renderGoreleaserConfig bool) {
-	if !cfg.Nix.Enabled.UnwrapOr(true) {
+	if !cfg.Nix.IsEnabled() {
 		return
 	}
-	logg.Debug("rendering configs for Nix")
 
 	goVersionSlice := strings.Split(core.DefaultGoVersion, ".")
 	goPackage := fmt.Sprintf("go_%s_%s", goVersionSlice[0], goVersionSlice[1])
-	packages := []string{
-		goPackage,
-		"addlicense",
-		"go-licence-detector",
-		"gotools # goimports",
+	packages := []string{goPackage}
+	// Go tools that are declared in go.mod are run with `go tool` and do not need to be installed
+	addTool := func(name, pkg string) {
+		if !sr.HasGoTool(name) {
+			packages = append(packages, pkg)
+		}
 	}
+	// nixpkgs only has one version of each tool, so the versions from the `tools` section are noted next to the packages;
+	// `make prepare-static-check` warns if the installed versions differ
+	addTool("addlicense", "addlicense # tools.addlicense: "+cfg.Tools.GetAddlicenseVersion())
+	addTool("go-licence-detector", "go-licence-detector # tools.goLicenceDetector: "+cfg.Tools.GetGoLicenceDetectorVersion())
+	addTool("goimports", "gotools # goimports, tools.goimports: "+cfg.Tools.GetGoimportsVersion())
 	if cfg.GolangciLint.CreateConfig {
-		packages = append(packages, "golangci-lint")
+		addTool("golangci-lint", "golangci-lint # tools.golangciLint: "+cfg.Tools.GetGolangciLintVersion())
 	}
 	if renderGoreleaserConfig {
 		// syft is used by goreleaser to generate an SBOM
 		packages = append(packages, "goreleaser", "syft")
 	}
-	runControllerGen := cfg.ControllerGen.Enabled.UnwrapOr(sr.KubernetesController)
+	runControllerGen := cfg.ControllerGen.IsEnabled(sr.KubernetesController)
 	if runControllerGen {
-		packages = append(packages, "kubernetes-controller-tools # controller-gen")
-		packages = append(packages, "setup-envtest")
+		addTool("controller-gen", "kubernetes-controller-tools # controller-gen, tools.controllerGen: "+cfg.Tools.GetControllerGenVersion())
+		addTool("setup-envtest", "setup-envtest # tools.setupEnvtest: "+cfg.Tools.GetSetupEnvtestVersion())
+	}
+	// services for running the unit tests and integration tests locally
+	services := sr.TestServices()
+	if cfg.Test.Integration.Enabled {
+		services = append(services, cfg.Test.Integration.Services...)
 	}
-	if sr.UsesPostgres {
-		packages = append(packages, "postgresql_"+core.DefaultPostgresVersion)
+	slices.Sort(services)
+	for _, name := range slices.Compact(services) {
+		packages = append(packages, core.TestServices[name].NixPackage)
 	}
 	if cfg.Renovate.Enabled {
 		packages = append(packages, "renovate")
@@ -59,7 +93,7 @@ func RenderShell(cfg core.Configuration, sr golang.ScanResult, renderGoreleaserC
 		packages = append(packages, "reuse")
 	}
 	if cfg.Typos.IsEnabled() {
-		packages = append(packages, "typos")
+		packages = append(packages, "typos # tools.typos: "+cfg.Tools.GetTyposVersion())
 	}
 	packages = append(packages, cfg.Nix.ExtraPackages...)
 
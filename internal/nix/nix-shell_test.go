@@ -14,8 +14,18 @@ import (
 	"github.com/sapcc/go-makefile-maker/internal/core"
 	"github.com/sapcc/go-makefile-maker/internal/envrc"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
+// renderIntoTempDir makes all files rendered during the test go into a temporary directory, which is returned.
+func renderIntoTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	previous := util.SetOutput(util.NewDirFS(dir))
+	t.Cleanup(func() { util.SetOutput(previous) })
+	return dir
+}
+
 // assertFileExists checks if a file exists and fails the test if the expectation is not met.
 func assertFileExists(t *testing.T, path string, shouldExist bool) {
 	t.Helper()
@@ -114,7 +124,7 @@ func TestRenderShell_ConfigurationOptions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Chdir(t.TempDir())
+			dir := renderIntoTempDir(t)
 
 			cfg := core.Configuration{
 				Nix:   tt.nixConfig,
@@ -125,14 +135,14 @@ func TestRenderShell_ConfigurationOptions(t *testing.T) {
 			RenderShell(cfg, sr, false)
 			envrc.RenderEnvRc(cfg)
 
-			assertFileExists(t, "shell.nix", tt.expectShellNix)
-			assertFileExists(t, ".envrc", tt.expectEnvrc)
+			assertFileExists(t, filepath.Join(dir, "shell.nix"), tt.expectShellNix)
+			assertFileExists(t, filepath.Join(dir, ".envrc"), tt.expectEnvrc)
 		})
 	}
 }
 
 func TestRenderShell_WithExtraPackages(t *testing.T) {
-	t.Chdir(t.TempDir())
+	dir := renderIntoTempDir(t)
 
 	cfg := core.Configuration{
 		Nix: core.NixConfig{
@@ -148,13 +158,13 @@ func TestRenderShell_WithExtraPackages(t *testing.T) {
 	RenderShell(cfg, sr, false)
 	envrc.RenderEnvRc(cfg)
 
-	assertFileExists(t, "shell.nix", true)
-	assertFileExists(t, ".envrc", true)
-	assertFileContains(t, "shell.nix", "jq", "curl")
+	assertFileExists(t, filepath.Join(dir, "shell.nix"), true)
+	assertFileExists(t, filepath.Join(dir, ".envrc"), true)
+	assertFileContains(t, filepath.Join(dir, "shell.nix"), "jq", "curl")
 }
 
 func TestRenderShell_WithVariables(t *testing.T) {
-	t.Chdir(t.TempDir())
+	dir := renderIntoTempDir(t)
 
 	cfg := core.Configuration{
 		Nix: core.NixConfig{
@@ -172,8 +182,8 @@ func TestRenderShell_WithVariables(t *testing.T) {
 	RenderShell(cfg, sr, false)
 	envrc.RenderEnvRc(cfg)
 
-	assertFileExists(t, ".envrc", true)
-	assertFileContains(t, ".envrc", "TEST_VAR")
+	assertFileExists(t, filepath.Join(dir, ".envrc"), true)
+	assertFileContains(t, filepath.Join(dir, ".envrc"), "TEST_VAR")
 }
 
 func TestRenderShell_PackageInclusion(t *testing.T) {
@@ -209,7 +219,7 @@ func TestRenderShell_PackageInclusion(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Chdir(t.TempDir())
+			dir := renderIntoTempDir(t)
 
 			cfg := core.Configuration{
 				Nix: core.NixConfig{
@@ -222,14 +232,13 @@ func TestRenderShell_PackageInclusion(t *testing.T) {
 			RenderShell(cfg, sr, tt.renderGoreleaserConfig)
 			envrc.RenderEnvRc(cfg)
 
-			assertFileContains(t, "shell.nix", tt.expectedPackages...)
+			assertFileContains(t, filepath.Join(dir, "shell.nix"), tt.expectedPackages...)
 		})
 	}
 }
 
 func TestRenderShell_ComplexScenario(t *testing.T) {
-	tempDir := t.TempDir()
-	t.Chdir(tempDir)
+	tempDir := renderIntoTempDir(t)
 
 	cfg := core.Configuration{
 		Nix: core.NixConfig{
@@ -258,6 +267,7 @@ func TestRenderShell_ComplexScenario(t *testing.T) {
 		},
 	}
 	sr := golang.ScanResult{
+		Modules:      []string{"github.com/lib/pq"},
 		UsesPostgres: true,
 	}
 
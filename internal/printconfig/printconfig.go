@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package printconfig implements the `go-makefile-maker print-config` subcommand.
+package printconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+)
+
+// Print prints the effective configuration for the repository in the working directory in the given format ("yaml" or "json").
+func Print(format string) {
+	if format != "yaml" && format != "json" {
+		logg.Fatal("unknown output format: %q (expected \"yaml\" or \"json\")", format)
+	}
+
+	cfg, doc := core.ReadConfiguration()
+	sr := golang.Scan()
+
+	explicit := make(map[string]bool)
+	inherited := make(map[string]string)
+	if doc.Root != nil {
+		walk(doc.Root, "", func(path string, node *yaml.Node) {
+			explicit[path] = true
+			if file := doc.FileOf(node); file != core.ConfigurationPath {
+				inherited[path] = file
+			}
+		})
+	}
+	cfg, sources := resolve(cfg, sr, explicit)
+
+	var out yaml.Node
+	must.Succeed(out.Encode(cfg))
+	addDetectedFields(&out, cfg)
+	walk(&out, "", func(path string, _ *yaml.Node) {
+		if _, exists := sources[path]; exists {
+			return
+		}
+		if file, exists := inherited[path]; exists {
+			sources[path] = source{Kind: "inherited", Note: "from " + file}
+		} else if explicit[path] {
+			sources[path] = source{Kind: "explicit"}
+		} else {
+			sources[path] = source{Kind: "default"}
+		}
+	})
+
+	switch format {
+	case "yaml":
+		walk(&out, "", func(path string, node *yaml.Node) {
+			node.LineComment = sources[path].String()
+		})
+		fmt.Printf("# Effective configuration as resolved from %s.\n", core.ConfigurationPath)
+		fmt.Println("# Each value is marked as either explicit (set in the config file), inherited (set in a base config from `extends`), default, or derived (from the contents of the repository).")
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		must.Succeed(enc.Encode(&out))
+		must.Succeed(enc.Close())
+	case "json":
+		var config any
+		must.Succeed(out.Decode(&config))
+		buf := must.Return(json.MarshalIndent(map[string]any{
+			"config":  config,
+			"sources": sources,
+		}, "", "  "))
+		fmt.Println(string(buf))
+	}
+}
+
+// source describes where a value in the effective configuration comes from.
+type source struct {
+	// Kind is either "explicit", "inherited", "default" or "derived".
+	Kind string `json:"source"`
+	// Note explains what a default or derived value depends on, or which base config an inherited value comes from.
+	Note string `json:"note,omitempty"`
+}
+
+// String returns the representation of this source that is used in the YAML output.
+func (s source) String() string {
+	if s.Note == "" {
+		return s.Kind
+	}
+	return fmt.Sprintf("%s (%s)", s.Kind, s.Note)
+}
+
+// walk calls the action for each leaf node below the given node, i.e. for each scalar and each empty collection.
+// The paths given to the action look like "githubWorkflow.ci.runOn[0]".
+func walk(node *yaml.Node, path string, action func(path string, node *yaml.Node)) {
+	switch {
+	case node.Kind == yaml.DocumentNode:
+		for _, child := range node.Content {
+			walk(child, path, action)
+		}
+	case node.Kind == yaml.MappingNode && len(node.Content) > 0:
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			key := node.Content[idx].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			walk(node.Content[idx+1], key, action)
+		}
+	case node.Kind == yaml.SequenceNode && len(node.Content) > 0:
+		for idx, child := range node.Content {
+			walk(child, path+"["+strconv.Itoa(idx)+"]", action)
+		}
+	default:
+		action(path, node)
+	}
+}
+
+// addDetectedFields adds the fields to the encoded configuration that cannot be set in the config file.
+func addDetectedFields(out *yaml.Node, cfg core.Configuration) {
+	if cfg.GitHubWorkflow == nil {
+		return
+	}
+	for idx := 0; idx+1 < len(out.Content); idx += 2 {
+		if out.Content[idx].Value != "githubWorkflow" {
+			continue
+		}
+		ghw := out.Content[idx+1]
+		for _, field := range []struct {
+			Key   string
+			Value bool
+		}{
+			{"isSelfHostedRunner", cfg.GitHubWorkflow.IsSelfHostedRunner},
+			{"isSugarRunner", cfg.GitHubWorkflow.IsSugarRunner},
+		} {
+			ghw.Content = append(ghw.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.Key},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(field.Value)},
+			)
+		}
+	}
+}
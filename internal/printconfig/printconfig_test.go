@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package printconfig
+
+import (
+	"testing"
+
+	. "go.xyrillian.de/gg/option"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+)
+
+func TestWalk(t *testing.T) {
+	var doc yaml.Node
+	err := yaml.Unmarshal([]byte("nix:\n  enabled: false\nbinaries:\n  - name: foo\ngithubWorkflow:\n  ci:\n    runOn: []\n"), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	walk(&doc, "", func(path string, _ *yaml.Node) {
+		paths = append(paths, path)
+	})
+	expected := []string{"nix.enabled", "binaries[0].name", "githubWorkflow.ci.runOn"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected paths %v, but got %v", expected, paths)
+	}
+	for idx := range expected {
+		if paths[idx] != expected[idx] {
+			t.Errorf("expected paths %v, but got %v", expected, paths)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cfg := core.Configuration{
+		Metadata:       core.Metadata{URL: "https://github.com/sapcc/example"},
+		Nix:            core.NixConfig{Enabled: Some(false)},
+		GitHubWorkflow: &core.GithubWorkflowConfiguration{},
+	}
+	cfg.GitHubWorkflow.Global.DefaultBranch = "main"
+	cfg.Makefile.Targets = []core.MakefileTarget{{Name: "generate", Category: Some("build")}}
+	cfg.GitHubWorkflow.PushContainerToGhcr.Platforms = "linux/amd64,linux/arm64"
+	explicit := map[string]bool{
+		"metadata.url":                                 true,
+		"nix.enabled":                                  true,
+		"githubWorkflow.global.defaultBranch":          true,
+		"githubWorkflow.pushContainerToGhcr.platforms": true,
+	}
+	sr := golang.ScanResult{GoVersionMajorMinor: "1.26", KubernetesController: true}
+
+	resolved, sources := resolve(cfg, sr, explicit)
+
+	expectedValues := map[string][2]any{
+		"nix.enabled":                           {resolved.Nix.Enabled, Some(false)},
+		"envRc.enabled":                         {resolved.EnvRc.Enabled, Some(false)},
+		"controllerGen.enabled":                 {resolved.ControllerGen.Enabled, Some(true)},
+		"dockerfile.crossCompile":               {resolved.Dockerfile.CrossCompile, Some(true)},
+		"license.addHeaders":                    {resolved.License.AddHeaders, Some(true)},
+		"githubWorkflow.global.goVersion":       {resolved.GitHubWorkflow.Global.GoVersion, Some(core.DefaultGoVersion)},
+		"githubWorkflow.release.enabled":        {resolved.GitHubWorkflow.Release.Enabled, Some(false)},
+		"githubWorkflow.securityChecks.queries": {resolved.GitHubWorkflow.SecurityChecks.Queries, Some("security-extended")},
+		"renovate.goVersion":                    {resolved.Renovate.GoVersion, "1.26"},
+		"makefile.targets[0].category":          {resolved.Makefile.Targets[0].Category, Some("build")},
+		"makefile.targets[0].phony":             {resolved.Makefile.Targets[0].Phony, Some(true)},
+	}
+	for path, values := range expectedValues {
+		if values[0] != values[1] {
+			t.Errorf("expected %s to be resolved to %v, but got %v", path, values[1], values[0])
+		}
+	}
+
+	expectedSources := map[string]string{
+		"envRc.enabled":                     "default (follows nix.enabled)",
+		"controllerGen.enabled":             "derived (whether go.mod requires sigs.k8s.io/controller-runtime)",
+		"githubWorkflow.global.goVersion":   "default",
+		"githubWorkflow.isSelfHostedRunner": "derived (from metadata.url)",
+		"renovate.goVersion":                "derived (from go.mod)",
+	}
+	for path, expected := range expectedSources {
+		if actual := sources[path].String(); actual != expected {
+			t.Errorf("expected source of %s to be %q, but got %q", path, expected, actual)
+		}
+	}
+	// explicit values are not listed by resolve()
+	for _, path := range []string{"nix.enabled", "githubWorkflow.global.defaultBranch", "golang.enableVendoring"} {
+		if src, exists := sources[path]; exists {
+			t.Errorf("expected no source for %s, but got %q", path, src.String())
+		}
+	}
+
+	// the input configuration must not be changed
+	if cfg.GitHubWorkflow.Global.GoVersion.IsSome() || cfg.EnvRc.Enabled.IsSome() || cfg.Makefile.Targets[0].Phony.IsSome() {
+		t.Error("expected resolve() to not change the input configuration")
+	}
+}
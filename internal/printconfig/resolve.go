@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package printconfig
+
+import (
+	"fmt"
+	"slices"
+
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/renovate"
+)
+
+// resolve fills in all settings of the given configuration that are not set explicitly in the config file.
+// The sources of all filled-in values are returned, keyed by their path in the configuration (e.g. "nix.enabled").
+// All other values are either set explicitly or not filled in because their zero value is the default.
+//
+// The effective values are computed by the same helper methods that the generators use,
+// so that the result does not diverge from what actually gets rendered.
+func resolve(cfg core.Configuration, sr golang.ScanResult, explicit map[string]bool) (core.Configuration, map[string]source) {
+	// All effective values are computed from the original configuration
+	// since filling in one setting may change the defaults of other settings.
+	orig := cfg
+	if cfg.GitHubWorkflow != nil {
+		ghwCfg := *cfg.GitHubWorkflow
+		cfg.GitHubWorkflow = &ghwCfg
+	}
+	cfg.DetectEnvironment()
+
+	sources := make(map[string]source)
+	byDefault := source{Kind: "default"}
+	forSAPProjects := source{Kind: "default", Note: "follows metadata.url"}
+
+	fill(sources, "makefile.enabled", &cfg.Makefile.Enabled, orig.Makefile.IsEnabled(), byDefault)
+	cfg.Makefile.Targets = slices.Clone(orig.Makefile.Targets)
+	for idx, target := range orig.Makefile.Targets {
+		path := fmt.Sprintf("makefile.targets[%d]", idx)
+		fill(sources, path+".category", &cfg.Makefile.Targets[idx].Category, target.GetCategory(), byDefault)
+		fill(sources, path+".phony", &cfg.Makefile.Targets[idx].Phony, target.IsPhony(), byDefault)
+	}
+	fill(sources, "nix.enabled", &cfg.Nix.Enabled, orig.Nix.IsEnabled(), byDefault)
+	fill(sources, "envRc.enabled", &cfg.EnvRc.Enabled, orig.EnvRc.IsEnabled(orig.Nix),
+		source{Kind: "default", Note: "follows nix.enabled"})
+	fill(sources, "reuse.enabled", &cfg.Reuse.Enabled, orig.Reuse.IsEnabled(), byDefault)
+	fill(sources, "typos.enabled", &cfg.Typos.Enabled, orig.Typos.IsEnabled(), byDefault)
+	fill(sources, "shellCheck.enabled", &cfg.ShellCheck.Enabled, orig.ShellCheck.IsEnabled(), byDefault)
+	fill(sources, "controllerGen.enabled", &cfg.ControllerGen.Enabled, orig.ControllerGen.IsEnabled(sr.KubernetesController),
+		source{Kind: "derived", Note: "whether go.mod requires sigs.k8s.io/controller-runtime"})
+	fill(sources, "dockerfile.withTestTarget", &cfg.Dockerfile.WithTestTarget, orig.Dockerfile.ShouldRenderTestTarget(), byDefault)
+	fill(sources, "dockerfile.crossCompile", &cfg.Dockerfile.CrossCompile, orig.ShouldCrossCompile(),
+		source{Kind: "default", Note: "follows githubWorkflow.pushContainerToGhcr.platforms"})
+	fill(sources, "goReleaser.createConfig", &cfg.GoReleaser.CreateConfig, orig.ShouldRenderGoReleaserConfig(),
+		source{Kind: "default", Note: "follows githubWorkflow.release.enabled"})
+	fill(sources, "license.addHeaders", &cfg.License.AddHeaders, orig.ShouldAddLicenseHeaders(), forSAPProjects)
+	fill(sources, "license.checkDependencies", &cfg.License.CheckDependencies, orig.ShouldCheckLicenseDependencies(), forSAPProjects)
+	fill(sources, "license.copyright", &cfg.License.Copyright, orig.License.GetCopyright(), byDefault)
+	fill(sources, "license.spdx", &cfg.License.SPDX, orig.License.GetSPDX(), byDefault)
+	fill(sources, "testPackages.fuzz.time", &cfg.Test.Fuzz.Time, orig.Test.Fuzz.GetTime(), byDefault)
+	fill(sources, "testPackages.integration.tags", &cfg.Test.Integration.Tags, orig.Test.Integration.GetTags(), byDefault)
+	fill(sources, "tools.addlicense", &cfg.Tools.Addlicense, orig.Tools.GetAddlicenseVersion(), byDefault)
+	fill(sources, "tools.controllerGen", &cfg.Tools.ControllerGen, orig.Tools.GetControllerGenVersion(), byDefault)
+	fill(sources, "tools.goimports", &cfg.Tools.Goimports, orig.Tools.GetGoimportsVersion(), byDefault)
+	fill(sources, "tools.goLicenceDetector", &cfg.Tools.GoLicenceDetector, orig.Tools.GetGoLicenceDetectorVersion(), byDefault)
+	fill(sources, "tools.golangciLint", &cfg.Tools.GolangciLint, orig.Tools.GetGolangciLintVersion(), byDefault)
+	fill(sources, "tools.setupEnvtest", &cfg.Tools.SetupEnvtest, orig.Tools.GetSetupEnvtestVersion(), byDefault)
+	fill(sources, "tools.shellcheck", &cfg.Tools.Shellcheck, orig.Tools.GetShellcheckVersion(), byDefault)
+	fill(sources, "tools.typos", &cfg.Tools.Typos, orig.Tools.GetTyposVersion(), byDefault)
+
+	if orig.GitHubWorkflow != nil {
+		ghwCfg := *orig.GitHubWorkflow
+		fill(sources, "githubWorkflow.global.goVersion", &cfg.GitHubWorkflow.Global.GoVersion, ghwCfg.GetGoVersion(), byDefault)
+		if !explicit["githubWorkflow.global.defaultBranch"] && ghwCfg.Global.DefaultBranch != "" {
+			// filled in by Configuration.Validate() using core.DetectDefaultBranch()
+			sources["githubWorkflow.global.defaultBranch"] = source{Kind: "derived", Note: "from the HEAD of the origin remote"}
+		}
+		fill(sources, "githubWorkflow.license.enabled", &cfg.GitHubWorkflow.License.Enabled, ghwCfg.License.IsEnabled(), byDefault)
+		fill(sources, "githubWorkflow.release.enabled", &cfg.GitHubWorkflow.Release.Enabled, ghwCfg.Release.IsEnabled(orig.GoReleaser),
+			source{Kind: "default", Note: "follows goReleaser.createConfig"})
+		fill(sources, "githubWorkflow.release.releasePR", &cfg.GitHubWorkflow.Release.ReleasePR, ghwCfg.Release.IsReleasePREnabled(orig.GoReleaser),
+			source{Kind: "default", Note: "follows githubWorkflow.release.enabled"})
+		fill(sources, "githubWorkflow.securityChecks.enabled", &cfg.GitHubWorkflow.SecurityChecks.Enabled, ghwCfg.SecurityChecks.IsEnabled(), byDefault)
+		fill(sources, "githubWorkflow.securityChecks.queries", &cfg.GitHubWorkflow.SecurityChecks.Queries, ghwCfg.SecurityChecks.GetQueries(), byDefault)
+		// the Helm chart workflow is only rendered if the path is set
+		if helmCfg := ghwCfg.PushHelmChartToGhcr; helmCfg.Path.IsSome() {
+			fill(sources, "githubWorkflow.pushHelmChartToGhcr.lint", &cfg.GitHubWorkflow.PushHelmChartToGhcr.Lint, helmCfg.ShouldLint(), byDefault)
+			fill(sources, "githubWorkflow.pushHelmChartToGhcr.dependencyUpdate", &cfg.GitHubWorkflow.PushHelmChartToGhcr.DependencyUpdate, helmCfg.ShouldUpdateDependencies(), byDefault)
+		}
+		sources["githubWorkflow.isSelfHostedRunner"] = source{Kind: "derived", Note: "from metadata.url"}
+		sources["githubWorkflow.isSugarRunner"] = source{Kind: "derived", Note: "from metadata.url"}
+	}
+
+	if cfg.Golang.EnableVendoring && !orig.Golang.EnableVendoring {
+		sources["golang.enableVendoring"] = source{Kind: "derived", Note: "vendor/modules.txt exists"}
+	}
+	if cfg.Renovate.GoVersion == "" {
+		cfg.Renovate.GoVersion = renovate.GoVersion(orig, sr)
+		if cfg.Renovate.GoVersion != "" {
+			sources["renovate.goVersion"] = source{Kind: "derived", Note: "from go.mod"}
+		}
+	}
+
+	return cfg, sources
+}
+
+// fill sets an Option field to its effective value if it was not set in the config file.
+func fill[T any](sources map[string]source, path string, field *Option[T], value T, src source) {
+	if field.IsSome() {
+		return
+	}
+	*field = Some(value)
+	sources[path] = src
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/sapcc/go-bits/logg"
@@ -15,6 +16,7 @@ import (
 	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
@@ -40,6 +42,45 @@ type config struct {
 	SemanticCommits                            string             `json:"semanticCommits,omitempty"`
 }
 
+// Generator renders the Renovate configuration.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "renovate"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Renovate.Enabled
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	cfg := ctx.Config
+	cfg.Renovate.GoVersion = GoVersion(cfg, ctx.ScanResult)
+	RenderConfig(cfg, ctx.ScanResult, ctx.OwnedFilesOf("github-workflows"))
+}
+
+// GoVersion returns the Go version that Go updates are restricted to, which defaults to the Go version from go.mod.
+func GoVersion(cfg core.Configuration, sr golang.ScanResult) string {
+	if cfg.Renovate.GoVersion != "" {
+		return cfg.Renovate.GoVersion
+	}
+	return sr.GoVersionMajorMinor
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".github/renovate.json5"}
+}
+
+// DependsOn implements the generator.WithDependencies interface.
+func (Generator) DependsOn() []string {
+	// updates of generated workflows are disabled since they would be overwritten anyway
+	return []string{"github-workflows"}
+}
+
 // RenderConfig writes the renovate configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration, scanResult golang.ScanResult, generatedGHWorkflowPaths []string) {
 	isGoMakefileMakerRepo := scanResult.ModulePath == "github.com/sapcc/go-makefile-maker"
@@ -178,21 +219,31 @@ func RenderConfig(cfg core.Configuration, scanResult golang.ScanResult, generate
 	encoder.SetEscapeHTML(false) // in order to preserve `<` in allowedVersions field
 	must.Succeed(encoder.Encode(renovateConfig))
 
-	must.Succeed(os.MkdirAll(".github", 0750))
-	must.Succeed(os.RemoveAll("renovate.json"))
-	must.Succeed(os.RemoveAll(".github/renovate.json"))
 	must.Succeed(util.WriteFile(".github/renovate.json5", buf.Bytes()))
 
+	validateConfig(buf.Bytes())
+}
+
+// validateConfig runs renovate-config-validator (if available) on the given config file contents.
+// The contents are validated from a temporary file, so that this also works when
+// the generated file is not written into the working directory.
+func validateConfig(contents []byte) {
 	validator, err := exec.LookPath("renovate-config-validator")
 	if err != nil {
 		logg.Info("renovate-config-validator not found in PATH, skipping validation of generated renovate.json5 file")
-	} else {
-		logg.Debug("-> running renovate-config-validator")
-		cmd := exec.Command(validator)
-		cmd.Stderr = os.Stderr
-		output, err := cmd.Output()
-		if err != nil {
-			logg.Fatal(string(output))
-		}
+		return
+	}
+
+	tmpDir := must.Return(os.MkdirTemp("", "go-makefile-maker-*"))
+	defer os.RemoveAll(tmpDir)
+	tmpPath := filepath.Join(tmpDir, "renovate.json5")
+	must.Succeed(os.WriteFile(tmpPath, contents, 0o666))
+
+	logg.Debug("-> running renovate-config-validator")
+	cmd := exec.Command(validator, tmpPath)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		logg.Fatal(string(output))
 	}
 }
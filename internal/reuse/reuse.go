@@ -17,6 +17,7 @@ import (
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
@@ -28,6 +29,35 @@ var (
 	goLicenceDetectorTemplate []byte
 )
 
+// Generator renders the REUSE configuration.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "reuse"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Reuse.IsEnabled()
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config, ctx.ScanResult)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{"REUSE.toml"}
+}
+
+// DependsOn implements the generator.WithDependencies interface.
+func (Generator) DependsOn() []string {
+	// the license scan rules rendered alongside the Makefile are needed to run go-licence-detector
+	return []string{"makefile"}
+}
+
 // RenderConfig writes the reuse configuration files from the provided config and scan results.
 func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 	// If disabled, the REUSE.toml file should not be overridden.
@@ -48,27 +78,36 @@ func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 		_ = must.Return(tmpGLDT.Seek(0, 0))
 
 		// otherwise we might miss some direct dependencies which is really strange...
-		logg.Debug("-> running go-mod-tidy")
-		cmd := exec.Command("go", "mod", "tidy")
-		cmd.Stderr = os.Stderr
-		output, err := cmd.Output()
-		if err != nil {
-			logg.Fatal(string(output))
+		// (in a dry run, we must not modify go.mod and go.sum, so we need to trust that they are tidy already)
+		if !util.IsDryRun() {
+			logg.Debug("-> running go-mod-tidy")
+			cmd := exec.Command("go", "mod", "tidy")
+			cmd.Stderr = os.Stderr
+			output, err := cmd.Output()
+			if err != nil {
+				logg.Fatal(string(output))
+			}
 		}
 
 		_ = must.Return(exec.LookPath("go-licence-detector"))
 
+		// The rules and overrides files are rendered by package makefile, but are not necessarily on disk yet (e.g. in a dry run).
+		rulesPath := copyToTempFile(".license-scan-rules.json")
+		defer os.Remove(rulesPath)
+		overridesPath := copyToTempFile(".license-scan-overrides.jsonl")
+		defer os.Remove(overridesPath)
+
 		// Create temporary file for output
 		tmpOutput := must.Return(os.CreateTemp("", "go-makefile-maker-output-*"))
 		defer os.Remove(tmpOutput.Name())
 		tmpOutput.Close() // Close so external command can write to it
 
 		logg.Debug("-> running go-licence-detector")
-		cmd = exec.Command("sh", "-c", //nolint:gosec // Command is run by the user
+		cmd := exec.Command("sh", "-c", //nolint:gosec // Command is run by the user
 			// On Linux we would just use /dev/stdout but that does not work on ✨ macOS ✨
-			fmt.Sprintf("go list -m -mod=readonly -json all | go-licence-detector -includeIndirect -rules .license-scan-rules.json -overrides .license-scan-overrides.jsonl -depsOut %s -depsTemplate /dev/fd/3", tmpOutput.Name()))
+			fmt.Sprintf("go list -m -mod=readonly -json all | go-licence-detector -includeIndirect -rules %s -overrides %s -depsOut %s -depsTemplate /dev/fd/3", rulesPath, overridesPath, tmpOutput.Name()))
 		cmd.ExtraFiles = []*os.File{tmpGLDT}
-		output, err = cmd.CombinedOutput() // Capture output only in case of an error
+		output, err := cmd.CombinedOutput() // Capture output only in case of an error
 		if err != nil {
 			logg.Fatal(string(output))
 		}
@@ -118,3 +157,11 @@ func RenderConfig(cfg core.Configuration, sr golang.ScanResult) {
 		"IsSAPProject": cfg.Metadata.IsSAPProject(),
 	}))
 }
+
+// copyToTempFile copies a generated file into a temporary file and returns the path of the temporary file.
+func copyToTempFile(fileName string) string {
+	tmpFile := must.Return(os.CreateTemp("", "go-makefile-maker-*"))
+	_ = must.Return(tmpFile.Write(must.Return(util.ReadFile(fileName))))
+	must.Succeed(tmpFile.Close())
+	return tmpFile.Name()
+}
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package scaffold implements the `go-makefile-maker init` subcommand.
+package scaffold
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"go/build"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
+)
+
+var (
+	//go:embed Makefile.maker.yaml.tmpl
+	configTemplate string
+)
+
+// repoInfo contains everything that was found out about the repository in the working directory.
+type repoInfo struct {
+	ScanResult    golang.ScanResult
+	URL           string
+	DefaultBranch string
+	Binaries      []core.BinaryConfiguration
+	HasDockerfile bool
+}
+
+// WriteConfig writes an initial Makefile.maker.yaml for the repository in the working directory.
+func WriteConfig() {
+	_, err := os.Stat(core.ConfigurationPath)
+	if err == nil {
+		logg.Fatal("%s already exists, refusing to overwrite it", core.ConfigurationPath)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		must.Succeed(err)
+	}
+
+	sr := golang.Scan()
+	_, err = os.Stat("Dockerfile")
+	info := repoInfo{
+		ScanResult:    sr,
+		URL:           detectURL(sr),
+		DefaultBranch: detectDefaultBranch(),
+		Binaries:      findBinaries(sr),
+		HasDockerfile: err == nil,
+	}
+	contents := renderConfig(info)
+
+	// the generated config must be accepted when running go-makefile-maker for the first time
+	_, _, errs := core.ParseConfiguration(contents)
+	for _, err := range errs {
+		logg.Fatal("generated config is invalid: %s", err.Error())
+	}
+
+	must.Succeed(util.WriteFile(core.ConfigurationPath, contents))
+	logg.Info("wrote %s, please review it and then run go-makefile-maker to generate the Makefile", core.ConfigurationPath)
+}
+
+func renderConfig(info repoInfo) []byte {
+	isGolang := info.ScanResult.GoVersion != ""
+	// metadata.url is required for GitHub workflows and the Dockerfile
+	withGitHubWorkflow := info.URL != ""
+	// a Dockerfile that exists already is most likely hand-written, so we do not replace it by default
+	suggestDockerfile := withGitHubWorkflow && len(info.Binaries) > 0
+
+	var binaries []core.BinaryConfiguration
+	for _, bin := range info.Binaries {
+		binaries = append(binaries, core.BinaryConfiguration{
+			Name:        yamlScalar(bin.Name),
+			FromPackage: yamlScalar(bin.FromPackage),
+			InstallTo:   yamlScalar(bin.InstallTo),
+		})
+	}
+
+	t := template.Must(template.New(core.ConfigurationPath).Parse(configTemplate))
+	var buf bytes.Buffer
+	must.Succeed(t.Execute(&buf, map[string]any{
+		"URL":                yamlScalar(info.URL),
+		"DefaultBranch":      yamlScalar(info.DefaultBranch),
+		"Binaries":           binaries,
+		"IsGolang":           isGolang,
+		"WithDockerfile":     suggestDockerfile && !info.HasDockerfile,
+		"KeepDockerfile":     suggestDockerfile && info.HasDockerfile,
+		"WithControllerGen":  info.ScanResult.KubernetesController,
+		"WithGitHubWorkflow": withGitHubWorkflow,
+		// the Renovate config refers to the GitHub workflow config
+		"WithRenovate": withGitHubWorkflow,
+	}))
+	return buf.Bytes()
+}
+
+// yamlScalar quotes the given string if necessary to use it as a YAML scalar.
+func yamlScalar(value string) string {
+	if value == "" {
+		return ""
+	}
+	return strings.TrimSuffix(string(must.Return(yaml.Marshal(value))), "\n")
+}
+
+// findBinaries looks for main packages in the repository root and in the direct subdirectories of cmd/.
+func findBinaries(sr golang.ScanResult) []core.BinaryConfiguration {
+	if sr.ModulePath == "" {
+		return nil
+	}
+
+	var result []core.BinaryConfiguration
+	if isMainPackage(".") {
+		result = append(result, core.BinaryConfiguration{
+			Name:        binaryNameFromModulePath(sr.ModulePath),
+			FromPackage: ".",
+			InstallTo:   "bin/",
+		})
+	}
+
+	entries, err := os.ReadDir("cmd")
+	if errors.Is(err, fs.ErrNotExist) {
+		return result
+	}
+	must.Succeed(err)
+	for _, entry := range entries {
+		dir := filepath.Join("cmd", entry.Name())
+		if entry.IsDir() && isMainPackage(dir) {
+			result = append(result, core.BinaryConfiguration{
+				Name:        entry.Name(),
+				FromPackage: "./" + filepath.ToSlash(dir),
+				InstallTo:   "bin/",
+			})
+		}
+	}
+	return result
+}
+
+func isMainPackage(dir string) bool {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		var noGoErr *build.NoGoError
+		if !errors.As(err, &noGoErr) {
+			logg.Error("cannot inspect Go package in %s: %s", dir, err.Error())
+		}
+		return false
+	}
+	return pkg.Name == "main"
+}
+
+var majorVersionSuffixRx = regexp.MustCompile(`^v[0-9]+$`)
+
+// binaryNameFromModulePath returns the name that `go install` would use for a main package in the module root.
+func binaryNameFromModulePath(modulePath string) string {
+	name := path.Base(modulePath)
+	if majorVersionSuffixRx.MatchString(name) {
+		name = path.Base(path.Dir(modulePath))
+	}
+	return name
+}
+
+// detectURL returns the web URL of the repository, based on the Git remote or on the module path.
+func detectURL(sr golang.ScanResult) string {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err == nil {
+		if result := webURLFromRemote(strings.TrimSpace(string(out))); result != "" {
+			return result
+		}
+	}
+
+	if strings.HasPrefix(sr.ModulePath, "github.com/") {
+		modulePath := sr.ModulePath
+		if majorVersionSuffixRx.MatchString(path.Base(modulePath)) {
+			modulePath = path.Dir(modulePath)
+		}
+		return "https://" + modulePath
+	}
+	return ""
+}
+
+var scpLikeRemoteRx = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// webURLFromRemote converts a Git remote URL like "git@github.com:foo/bar.git" into "https://github.com/foo/bar".
+// The empty string is returned if the remote URL cannot be understood.
+func webURLFromRemote(remote string) string {
+	var host, repoPath string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Hostname() == "" || !slices.Contains([]string{"https", "http", "ssh", "git"}, u.Scheme) {
+			return ""
+		}
+		host, repoPath = u.Hostname(), u.Path
+	} else {
+		match := scpLikeRemoteRx.FindStringSubmatch(remote)
+		if match == nil {
+			return ""
+		}
+		host, repoPath = match[1], match[2]
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if repoPath == "" {
+		return ""
+	}
+	return "https://" + host + "/" + repoPath
+}
+
+// detectDefaultBranch returns the default branch of the origin remote, or the current branch if the former is unknown.
+func detectDefaultBranch() string {
+	branch, err := core.DetectDefaultBranch()
+	if err == nil {
+		return branch
+	}
+	out, err := exec.Command("git", "branch", "--show-current").Output()
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
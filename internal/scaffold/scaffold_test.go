@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package scaffold
+
+import (
+	"bytes"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+)
+
+func TestWebURLFromRemote(t *testing.T) {
+	testCases := map[string]string{
+		"git@github.com:sapcc/go-makefile-maker.git":       "https://github.com/sapcc/go-makefile-maker",
+		"github.com:sapcc/go-makefile-maker":               "https://github.com/sapcc/go-makefile-maker",
+		"https://github.com/sapcc/go-makefile-maker.git":   "https://github.com/sapcc/go-makefile-maker",
+		"https://user@github.com/sapcc/go-makefile-maker/": "https://github.com/sapcc/go-makefile-maker",
+		"ssh://git@github.wdf.sap.corp:22/cc/example.git":  "https://github.wdf.sap.corp/cc/example",
+		"/srv/git/example.git":                             "",
+		"file:///srv/git/example.git":                      "",
+		"https://github.com":                               "",
+	}
+	for remote, expected := range testCases {
+		actual := webURLFromRemote(remote)
+		if actual != expected {
+			t.Errorf("expected %q to be converted into %q, but got %q", remote, expected, actual)
+		}
+	}
+}
+
+func TestBinaryNameFromModulePath(t *testing.T) {
+	testCases := map[string]string{
+		"github.com/sapcc/keppel":   "keppel",
+		"github.com/sapcc/limes/v2": "limes",
+		"example.com/foo/v2beta":    "v2beta",
+		"github.com/sapcc/v2":       "sapcc",
+	}
+	for modulePath, expected := range testCases {
+		actual := binaryNameFromModulePath(modulePath)
+		if actual != expected {
+			t.Errorf("expected binary name for %q to be %q, but got %q", modulePath, expected, actual)
+		}
+	}
+}
+
+func TestRenderConfig(t *testing.T) {
+	info := repoInfo{
+		ScanResult: golang.ScanResult{
+			ModulePath:           "github.com/sapcc/example",
+			GoVersion:            "1.25.0",
+			KubernetesController: true,
+		},
+		URL:           "https://github.com/sapcc/example",
+		DefaultBranch: "main",
+		Binaries: []core.BinaryConfiguration{
+			{Name: "example", FromPackage: ".", InstallTo: "bin/"},
+			{Name: "yes", FromPackage: "./cmd/yes", InstallTo: "bin/"},
+		},
+	}
+
+	var cfg core.Configuration
+	dec := yaml.NewDecoder(bytes.NewReader(renderConfig(info)))
+	dec.KnownFields(true)
+	err := dec.Decode(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Metadata.URL != info.URL {
+		t.Errorf("expected metadata.url = %q, but got %q", info.URL, cfg.Metadata.URL)
+	}
+	// "yes" would be parsed as a boolean if not quoted properly
+	if len(cfg.Binaries) != 2 || cfg.Binaries[1].Name != "yes" || cfg.Binaries[1].FromPackage != "./cmd/yes" {
+		t.Errorf("unexpected binaries: %#v", cfg.Binaries)
+	}
+	if cfg.GitHubWorkflow == nil || cfg.GitHubWorkflow.Global.DefaultBranch != "main" || !cfg.GitHubWorkflow.CI.Enabled {
+		t.Errorf("unexpected githubWorkflow: %#v", cfg.GitHubWorkflow)
+	}
+	if !cfg.Dockerfile.Enabled || !cfg.ControllerGen.Enabled.UnwrapOr(false) || !cfg.Renovate.Enabled || !cfg.GolangciLint.CreateConfig {
+		t.Errorf("expected dockerfile, controllerGen, renovate and golangciLint to be enabled")
+	}
+
+	// an existing Dockerfile must not be replaced
+	info.HasDockerfile = true
+	cfg = core.Configuration{}
+	err = yaml.Unmarshal(renderConfig(info), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Dockerfile.Enabled {
+		t.Errorf("expected dockerfile to not be enabled when a Dockerfile exists already")
+	}
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema generates the JSON schema for Makefile.maker.yaml from type core.Configuration.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/regexpext"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+)
+
+const (
+	// Path is where the generated schema is checked in, relative to the repository root.
+	Path = "Makefile.maker.schema.json"
+	// URL is where the checked-in schema can be downloaded from.
+	URL = "https://raw.githubusercontent.com/sapcc/go-makefile-maker/main/" + Path
+)
+
+// enums lists the accepted values for string fields (or for the items of string list fields).
+// The keys are in the same format as for descriptions, i.e. "TypeName.FieldName".
+var enums = map[string][]string{
+	"MakefileTarget.Category":               core.MakefileCategories,
+	"PushContainerToGhcrConfig.TagStrategy": core.TagStrategies,
+	"ReuseAnnotation.Precedence":            {"closest", "aggregate", "override"},
+}
+
+// schema is a subset of the JSON schema vocabulary.
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Type                 any                `json:"type,omitempty"` // either string or []string
+	Enum                 []string           `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // either false or *schema
+	Defs                 map[string]*schema `json:"$defs,omitempty"`
+}
+
+// Generate returns the JSON schema for the config file.
+func Generate() []byte {
+	b := builder{
+		descriptions: parseDescriptions(core.ConfigurationSource),
+		defs:         make(map[string]*schema),
+	}
+	root := b.forStruct(reflect.TypeFor[core.Configuration](), "Configuration")
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.ID = URL
+	root.Title = core.ConfigurationPath
+	root.Description = "Configuration file for go-makefile-maker <https://github.com/sapcc/go-makefile-maker>"
+	root.Defs = b.defs
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	must.Succeed(enc.Encode(root))
+	return buf.Bytes()
+}
+
+type builder struct {
+	// keys look like "TypeName.FieldName" or "TypeName.FieldName.NestedFieldName" for fields of anonymous structs
+	descriptions map[string]string
+	defs         map[string]*schema
+}
+
+// forType returns the schema for the given type.
+// For fields of anonymous structs, key is the key prefix for looking up their descriptions.
+func (b builder) forType(t reflect.Type, key string) *schema {
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		// yaml.v3 parses durations with time.ParseDuration()
+		return &schema{Type: "string", Pattern: `^([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+$`}
+	case t == reflect.TypeFor[regexpext.PlainRegexp]():
+		return &schema{Type: "string", Format: "regex"}
+	case isOption(t):
+		// Option[T] accepts everything that T accepts, and also null (which is the same as leaving the field out)
+		inner, _ := t.FieldByName("value")
+		s := b.forType(inner.Type, key)
+		if typeName, ok := s.Type.(string); ok {
+			s.Type = []string{typeName, "null"}
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.forType(t.Elem(), key)
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.Slice:
+		return &schema{Type: "array", Items: b.forType(t.Elem(), key)}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: b.forType(t.Elem(), key)}
+	case reflect.Interface:
+		// anything goes
+		return &schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.forStruct(t, key)
+		}
+		_, exists := b.defs[t.Name()]
+		if !exists {
+			b.defs[t.Name()] = nil // placeholder to avoid infinite recursion on recursive types
+			b.defs[t.Name()] = b.forStruct(t, t.Name())
+		}
+		return &schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		panic("cannot generate JSON schema for type " + t.String())
+	}
+}
+
+func (b builder) forStruct(t reflect.Type, key string) *schema {
+	s := &schema{
+		Type:                 "object",
+		Properties:           make(map[string]*schema),
+		AdditionalProperties: false,
+	}
+	for idx := range t.NumField() {
+		field := t.Field(idx)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "-" || name == "" {
+			continue
+		}
+
+		fieldKey := key + "." + field.Name
+		fieldSchema := b.forType(field.Type, fieldKey)
+		if values, exists := enums[fieldKey]; exists {
+			if fieldSchema.Items != nil {
+				fieldSchema.Items.Enum = values
+			} else {
+				fieldSchema.Enum = values
+			}
+		}
+
+		desc := b.descriptions[fieldKey]
+		if rest, ok := strings.CutPrefix(desc, "Deprecated: "); ok {
+			fieldSchema.Deprecated = true
+			desc = "Deprecated: " + rest
+		} else if rest, ok := strings.CutPrefix(desc, field.Name+" "); ok {
+			// doc comments on fields usually start with the Go field name, but users only know the YAML key
+			desc = name + " " + rest
+		}
+		fieldSchema.Description = desc
+
+		s.Properties[name] = fieldSchema
+	}
+	return s
+}
+
+func isOption(t reflect.Type) bool {
+	return t.PkgPath() == "go.xyrillian.de/gg/option" && strings.HasPrefix(t.Name(), "Option[")
+}
+
+// parseDescriptions collects the doc comments on all struct fields in the given Go source file.
+func parseDescriptions(source string) map[string]string {
+	file := must.Return(parser.ParseFile(token.NewFileSet(), "config.go", source, parser.ParseComments))
+
+	result := make(map[string]string)
+	var collect func(st *ast.StructType, key string)
+	collect = func(st *ast.StructType, key string) {
+		for _, field := range st.Fields.List {
+			doc := field.Doc.Text()
+			if doc == "" {
+				doc = field.Comment.Text()
+			}
+			for _, name := range field.Names {
+				fieldKey := key + "." + name.Name
+				if doc != "" {
+					result[fieldKey] = strings.Join(strings.Fields(doc), " ")
+				}
+				if nested, ok := field.Type.(*ast.StructType); ok {
+					collect(nested, fieldKey)
+				}
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if st, ok := typeSpec.Type.(*ast.StructType); ok {
+				collect(st, typeSpec.Name.Name)
+			}
+		}
+	}
+	return result
+}
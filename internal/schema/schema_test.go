@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaIsUpToDate(t *testing.T) {
+	checkedIn, err := os.ReadFile(filepath.Join("..", "..", Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(checkedIn, Generate()) {
+		t.Errorf("%s is out of date, please run `go run . schema > %s`", Path, Path)
+	}
+}
+
+func TestAllFieldsHaveDescriptions(t *testing.T) {
+	var s schema
+	err := json.Unmarshal(Generate(), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var check func(s *schema, path string)
+	check = func(s *schema, path string) {
+		for name, property := range s.Properties {
+			if property.Description == "" {
+				t.Errorf("%s.%s has no description, please add a doc comment to the respective field in core.Configuration", path, name)
+			}
+			check(property, path+"."+name)
+		}
+	}
+	check(&s, "Configuration")
+	for name, def := range s.Defs {
+		check(def, name)
+	}
+}
+
+func TestParseDescriptions(t *testing.T) {
+	source := `package core
+
+type Example struct {
+	// Name is the name.
+	// It spans two lines.
+	Name string ` + "`yaml:\"name\"`" + `
+	Other bool ` + "`yaml:\"other\"`" + ` // trailing comments count as well
+	Nested struct {
+		// Inner is nested.
+		Inner string ` + "`yaml:\"inner\"`" + `
+	} ` + "`yaml:\"nested\"`" + `
+}
+`
+	expected := map[string]string{
+		"Example.Name":         "Name is the name. It spans two lines.",
+		"Example.Other":        "trailing comments count as well",
+		"Example.Nested.Inner": "Inner is nested.",
+	}
+	actual := parseDescriptions(source)
+	if len(actual) != len(expected) {
+		t.Errorf("expected %v, but got %v", expected, actual)
+	}
+	for key, value := range expected {
+		if actual[key] != value {
+			t.Errorf("expected description of %s to be %q, but got %q", key, value, actual[key])
+		}
+	}
+}
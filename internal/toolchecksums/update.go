@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package toolchecksums implements the `go-makefile-maker --update-tool-checksums` maintainer command.
+package toolchecksums
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+)
+
+// Update downloads the release archives of the core.DefaultToolVersions for all core.ToolPlatforms
+// and adds their checksums to the table at core.ToolChecksumsPath. This must be run in the root of the go-makefile-maker repository.
+// Existing entries in the table are kept, so that projects that pin older versions can still verify their downloads.
+func Update() {
+	buf, err := os.ReadFile(core.ToolChecksumsPath)
+	if err != nil {
+		logg.Fatal("--update-tool-checksums must be run in the root of the go-makefile-maker repository: %s", err.Error())
+	}
+	var table core.ToolChecksumTable
+	must.Succeed(json.Unmarshal(buf, &table))
+	if table == nil {
+		table = make(core.ToolChecksumTable)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	for _, tool := range core.DownloadedTools {
+		version := core.DefaultToolVersions[tool]
+		if table[tool] == nil {
+			table[tool] = make(map[string]map[string]string)
+		}
+		checksums := make(map[string]string)
+		for _, platform := range core.ToolPlatforms {
+			url := core.ToolReleaseURL(tool, version, platform)
+			checksum, err := downloadChecksum(client, url)
+			if err != nil {
+				logg.Error("skipping %s %s for %s: %s", tool, version, platform, err.Error())
+				continue
+			}
+			if previous, exists := table[tool][version][platform]; exists && previous != checksum {
+				logg.Fatal("checksum of %s has changed from %s to %s, refusing to update the table", url, previous, checksum)
+			}
+			logg.Info("%s %s for %s: %s", tool, version, platform, checksum)
+			checksums[platform] = checksum
+		}
+		if len(checksums) > 0 {
+			table[tool][version] = checksums
+		}
+	}
+
+	buf = must.Return(json.MarshalIndent(table, "", "  "))
+	must.Succeed(os.WriteFile(core.ToolChecksumsPath, append(buf, '\n'), 0o666))
+}
+
+// downloadChecksum downloads the file at the given URL and returns its SHA-256 checksum in hex encoding.
+func downloadChecksum(client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
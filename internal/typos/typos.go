@@ -9,6 +9,8 @@ import (
 	"github.com/sapcc/go-bits/must"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
@@ -17,9 +19,33 @@ var (
 	typosConfigTemplate string
 )
 
+// Generator renders the typos configuration.
+type Generator struct{}
+
+// Name implements the generator.Generator interface.
+func (Generator) Name() string {
+	return "typos"
+}
+
+// Enabled implements the generator.Generator interface.
+func (Generator) Enabled(cfg core.Configuration, _ golang.ScanResult) bool {
+	return cfg.Typos.IsEnabled()
+}
+
+// Render implements the generator.Generator interface.
+func (Generator) Render(ctx generator.Context) {
+	RenderConfig(ctx.Config)
+}
+
+// OwnedFiles implements the generator.Generator interface.
+func (Generator) OwnedFiles(_ core.Configuration, _ golang.ScanResult) []string {
+	return []string{".typos.toml"}
+}
+
 // RenderConfig writes the typos configuration files from the provided config.
 func RenderConfig(cfg core.Configuration) {
-	extendExcludes := []string{"go.mod"}
+	// fuzz corpora (see `make fuzz`) contain random inputs
+	extendExcludes := []string{"go.mod", "testdata/fuzz/"}
 	if cfg.Golang.EnableVendoring {
 		extendExcludes = append(extendExcludes, "vendor/")
 	}
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// If the edit distance between two files exceeds this number of lines,
+// UnifiedDiff gives up on finding a minimal diff and replaces the whole file instead.
+// This bounds the memory usage of the diff algorithm.
+const maxDiffEditDistance = 2000
+
+// UnifiedDiff returns a diff between two versions of a file in the unified diff format,
+// with file headers like those produced by `git diff`.
+// A nil slice denotes a file that does not exist (i.e. a file that is created or removed).
+// If both versions are equal, the empty string is returned.
+func UnifiedDiff(path string, oldContents, newContents []byte) string {
+	if string(oldContents) == string(newContents) && (oldContents == nil) == (newContents == nil) {
+		return ""
+	}
+
+	var out strings.Builder
+	if oldContents == nil {
+		out.WriteString("--- /dev/null\n")
+	} else {
+		fmt.Fprintf(&out, "--- a/%s\n", path)
+	}
+	if newContents == nil {
+		out.WriteString("+++ /dev/null\n")
+	} else {
+		fmt.Fprintf(&out, "+++ b/%s\n", path)
+	}
+
+	edits := diffLines(splitLines(string(oldContents)), splitLines(string(newContents)))
+	for _, h := range groupIntoHunks(edits) {
+		h.render(&out)
+	}
+	return out.String()
+}
+
+// splitLines splits a text into lines, retaining the line terminators.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+type diffOp byte
+
+const (
+	opEqual  diffOp = ' '
+	opDelete diffOp = '-'
+	opInsert diffOp = '+'
+)
+
+type diffEdit struct {
+	op   diffOp
+	line string
+	// 0-based line indexes in the old and new text (only meaningful where the line exists in the respective text)
+	oldIdx int
+	newIdx int
+}
+
+// diffLines computes a minimal edit script between two lists of lines using Myers' algorithm.
+func diffLines(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxDiffEditDistance)
+
+	// v[k+offset] = furthest x reached on diagonal k; trace[d] = snapshot of v before round d
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // step down (insertion)
+			} else {
+				x = v[offset+k-1] + 1 // step right (deletion)
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return replaceAll(a, b)
+	}
+
+	// walk back through the trace to reconstruct the edit script (in reverse order)
+	var edits []diffEdit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		// trace[d] covers the diagonals -d-1..d+1
+		snapshot := trace[d]
+		get := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, diffEdit{op: opEqual, line: a[x-1], oldIdx: x - 1, newIdx: y - 1})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, diffEdit{op: opInsert, line: b[y-1], oldIdx: x, newIdx: y - 1})
+				y--
+			} else {
+				edits = append(edits, diffEdit{op: opDelete, line: a[x-1], oldIdx: x - 1, newIdx: y})
+				x--
+			}
+		}
+	}
+	slices.Reverse(edits)
+	return edits
+}
+
+// replaceAll is the fallback edit script when a minimal diff is too expensive to compute.
+func replaceAll(a, b []string) []diffEdit {
+	edits := make([]diffEdit, 0, len(a)+len(b))
+	for idx, line := range a {
+		edits = append(edits, diffEdit{op: opDelete, line: line, oldIdx: idx, newIdx: 0})
+	}
+	for idx, line := range b {
+		edits = append(edits, diffEdit{op: opInsert, line: line, oldIdx: len(a), newIdx: idx})
+	}
+	return edits
+}
+
+type diffHunk struct {
+	edits []diffEdit
+}
+
+// groupIntoHunks selects the changed lines and their context from a full edit script.
+func groupIntoHunks(edits []diffEdit) []diffHunk {
+	var (
+		hunks []diffHunk
+		start = -1 // index of first edit in current hunk
+		end   = -1 // index after last change in current hunk
+	)
+	for idx, e := range edits {
+		if e.op == opEqual {
+			continue
+		}
+		if start >= 0 && idx-end > 2*diffContextLines {
+			hunks = append(hunks, diffHunk{edits[start:min(end+diffContextLines, len(edits))]})
+			start = -1
+		}
+		if start < 0 {
+			start = max(idx-diffContextLines, 0)
+		}
+		end = idx + 1
+	}
+	if start >= 0 {
+		hunks = append(hunks, diffHunk{edits[start:min(end+diffContextLines, len(edits))]})
+	}
+	return hunks
+}
+
+func (h diffHunk) render(out *strings.Builder) {
+	var oldCount, newCount int
+	for _, e := range h.edits {
+		if e.op != opInsert {
+			oldCount++
+		}
+		if e.op != opDelete {
+			newCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n",
+		formatHunkRange(h.edits[0].oldIdx, oldCount),
+		formatHunkRange(h.edits[0].newIdx, newCount),
+	)
+
+	for _, e := range h.edits {
+		out.WriteByte(byte(e.op))
+		out.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// formatHunkRange formats a line range in a hunk header like GNU diff does.
+func formatHunkRange(startIdx, count int) string {
+	switch count {
+	case 0:
+		// for empty ranges, the line number refers to the line *before* the range
+		return fmt.Sprintf("%d,0", startIdx)
+	case 1:
+		return fmt.Sprintf("%d", startIdx+1)
+	default:
+		return fmt.Sprintf("%d,%d", startIdx+1, count)
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := strings.Join([]string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", ""}, "\n")
+	newText := strings.Join([]string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "NINE", "ten", "eleven", ""}, "\n")
+
+	expected := `--- a/example.txt
++++ b/example.txt
+@@ -1,3 +1,4 @@
++zero
+ one
+ two
+ three
+@@ -6,6 +7,6 @@
+ six
+ seven
+ eight
+-nine
++NINE
+ ten
+ eleven
+`
+	actual := UnifiedDiff("example.txt", []byte(oldText), []byte(newText))
+	if actual != expected {
+		t.Errorf("expected diff:\n%s\nbut got:\n%s", expected, actual)
+	}
+
+	// files that are created or removed
+	actual = UnifiedDiff("new.txt", nil, []byte("foo\nbar"))
+	expected = "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+foo\n+bar\n\\ No newline at end of file\n"
+	if actual != expected {
+		t.Errorf("expected diff:\n%s\nbut got:\n%s", expected, actual)
+	}
+	actual = UnifiedDiff("old.txt", []byte("foo\n"), nil)
+	expected = "--- a/old.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-foo\n"
+	if actual != expected {
+		t.Errorf("expected diff:\n%s\nbut got:\n%s", expected, actual)
+	}
+
+	// no changes
+	actual = UnifiedDiff("same.txt", []byte(oldText), []byte(oldText))
+	if actual != "" {
+		t.Errorf("expected empty diff, but got:\n%s", actual)
+	}
+}
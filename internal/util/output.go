@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sapcc/go-bits/logg"
+	. "go.xyrillian.de/gg/option"
+)
+
+// OutputFS is the interface through which all generated files are written or removed.
+type OutputFS interface {
+	// ReadFile returns the current contents of the file, including changes made through this OutputFS.
+	ReadFile(path string) ([]byte, error)
+	// WriteFile replaces the contents of the file, creating parent directories as necessary.
+	WriteFile(path string, contents []byte) error
+	// RemoveAll is like os.RemoveAll.
+	RemoveAll(path string) error
+}
+
+var output OutputFS = NewDirFS(".")
+
+// SetOutput replaces the OutputFS used by WriteFile, WriteFileFromTemplate, RemoveFile and ReadFile,
+// and returns the previous OutputFS. By default, all files are written into the working directory.
+func SetOutput(fs OutputFS) (previous OutputFS) {
+	previous, output = output, fs
+	return previous
+}
+
+// IsDryRun returns whether generated files are currently written somewhere other than the working directory,
+// i.e. collected in memory or written into a separate output directory. Renderers use this to skip side effects
+// like invoking external commands that modify files in the repository.
+func IsDryRun() bool {
+	fs := output
+	if r, ok := fs.(*recordingFS); ok {
+		fs = r.OutputFS
+	}
+	d, ok := fs.(DirFS)
+	return !ok || filepath.Clean(d.dir) != "."
+}
+
+// RecordWrites calls the given function and returns the paths of all files
+// that were written through WriteFile or WriteFileFromTemplate in the meantime.
+func RecordWrites(action func()) []string {
+	r := &recordingFS{OutputFS: output}
+	output = r
+	defer func() { output = r.OutputFS }()
+	action()
+	return r.paths
+}
+
+type recordingFS struct {
+	OutputFS
+	paths []string
+}
+
+func (r *recordingFS) WriteFile(path string, contents []byte) error {
+	r.paths = append(r.paths, filepath.Clean(path))
+	return r.OutputFS.WriteFile(path, contents)
+}
+
+// ReadFile reads a file through the current OutputFS.
+func ReadFile(fileName string) ([]byte, error) {
+	return output.ReadFile(fileName)
+}
+
+// RemoveFile removes a file (or directory) through the current OutputFS.
+// Files that do not exist are silently ignored.
+func RemoveFile(fileName string) error {
+	return output.RemoveAll(fileName)
+}
+
+// DirFS is an OutputFS that writes files into a directory on disk.
+type DirFS struct {
+	dir string
+}
+
+// NewDirFS returns a DirFS for the given directory.
+// Paths given to its methods are interpreted relative to that directory.
+func NewDirFS(dir string) DirFS {
+	return DirFS{dir}
+}
+
+// ReadFile implements the OutputFS interface.
+func (d DirFS) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(d.dir, path))
+}
+
+// WriteFile implements the OutputFS interface.
+func (d DirFS) WriteFile(path string, contents []byte) error {
+	path = filepath.Join(d.dir, path)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0o666)
+}
+
+// RemoveAll implements the OutputFS interface.
+func (d DirFS) RemoveAll(path string) error {
+	path = filepath.Join(d.dir, path)
+	_, err := os.Stat(path)
+	if err == nil {
+		logg.Debug("-> removing file %s", path)
+	}
+	return os.RemoveAll(path)
+}
+
+// MemoryFS is an OutputFS that does not touch the disk.
+// Instead, it collects all changes in memory on top of the working directory.
+type MemoryFS struct {
+	// key = path, value = new contents (or None if the file was removed)
+	files map[string]Option[[]byte]
+}
+
+// NewMemoryFS returns an empty MemoryFS.
+func NewMemoryFS() *MemoryFS {
+	return &MemoryFS{files: make(map[string]Option[[]byte])}
+}
+
+// ReadFile implements the OutputFS interface.
+func (m *MemoryFS) ReadFile(path string) ([]byte, error) {
+	contents, exists := m.files[filepath.Clean(path)]
+	if !exists {
+		return os.ReadFile(path)
+	}
+	if buf, ok := contents.Unpack(); ok {
+		return slices.Clone(buf), nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+}
+
+// WriteFile implements the OutputFS interface.
+func (m *MemoryFS) WriteFile(path string, contents []byte) error {
+	// never store a nil slice, so that empty files can be distinguished from nonexistent ones by UnifiedDiff
+	m.files[filepath.Clean(path)] = Some(append([]byte{}, contents...))
+	return nil
+}
+
+// RemoveAll implements the OutputFS interface.
+func (m *MemoryFS) RemoveAll(path string) error {
+	path = filepath.Clean(path)
+	for existing := range m.files {
+		if isBelow(existing, path) {
+			m.files[existing] = None[[]byte]()
+		}
+	}
+
+	// also record files on disk that would be removed
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			m.files[filepath.Clean(p)] = None[[]byte]()
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func isBelow(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// FileChange describes how a file in the working directory would be changed by a MemoryFS.
+type FileChange struct {
+	Path string
+	// None if the file does not exist (before) or would be removed (after).
+	OldContents Option[[]byte]
+	NewContents Option[[]byte]
+}
+
+// Changes returns all files in the MemoryFS whose contents differ from what is on disk, sorted by path.
+func (m *MemoryFS) Changes() ([]FileChange, error) {
+	var result []FileChange
+	for _, path := range slices.Sorted(maps.Keys(m.files)) {
+		oldContents := None[[]byte]()
+		buf, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			oldContents = Some(buf)
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, err
+		}
+
+		newContents := m.files[path]
+		oldBuf, oldExists := oldContents.Unpack()
+		newBuf, newExists := newContents.Unpack()
+		if oldExists == newExists && bytes.Equal(oldBuf, newBuf) {
+			continue
+		}
+		result = append(result, FileChange{
+			Path:        path,
+			OldContents: oldContents,
+			NewContents: newContents,
+		})
+	}
+	return result, nil
+}
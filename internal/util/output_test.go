@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryFSChanges(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for path, contents := range map[string]string{
+		"unchanged.txt": "foo\n",
+		"changed.txt":   "bar\n",
+		"removed.txt":   "baz\n",
+	} {
+		if err := os.WriteFile(path, []byte(contents), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewMemoryFS()
+	for path, contents := range map[string]string{
+		"unchanged.txt":   "foo\n",
+		"changed.txt":     "qux\n",
+		"sub/created.txt": "new\n",
+	} {
+		if err := m.WriteFile(path, []byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.RemoveAll("removed.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveAll("does-not-exist.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// reads must observe the changes in memory
+	buf, err := m.ReadFile("changed.txt")
+	if err != nil || string(buf) != "qux\n" {
+		t.Errorf("expected to read changed contents, but got %q (err = %v)", string(buf), err)
+	}
+	_, err = m.ReadFile("removed.txt")
+	if !os.IsNotExist(err) {
+		t.Errorf("expected removed file to not exist, but got err = %v", err)
+	}
+
+	// the disk must not be touched
+	buf, err = os.ReadFile("changed.txt")
+	if err != nil || string(buf) != "bar\n" {
+		t.Errorf("expected file on disk to be unchanged, but got %q (err = %v)", string(buf), err)
+	}
+
+	changes, err := m.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		path      string
+		oldExists bool
+		newExists bool
+	}{
+		{"changed.txt", true, true},
+		{"removed.txt", true, false},
+		{"sub/created.txt", false, true},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, but got %d: %#v", len(expected), len(changes), changes)
+	}
+	for idx, e := range expected {
+		c := changes[idx]
+		if c.Path != e.path || c.OldContents.IsSome() != e.oldExists || c.NewContents.IsSome() != e.newExists {
+			t.Errorf("expected change %#v, but got %#v", e, c)
+		}
+	}
+}
+
+func TestDirFS(t *testing.T) {
+	t.Chdir(t.TempDir())
+	outputDir := t.TempDir()
+	defer SetOutput(SetOutput(NewDirFS(outputDir)))
+
+	if !IsDryRun() {
+		t.Error("expected writing into an output directory to count as a dry run")
+	}
+	if err := WriteFile("sub/file.txt", []byte("foo\n")); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := ReadFile("sub/file.txt")
+	if err != nil || string(buf) != "foo\n" {
+		t.Errorf("expected to read written contents, but got %q (err = %v)", string(buf), err)
+	}
+
+	// the file must end up in the output directory, not in the working directory
+	buf, err = os.ReadFile(filepath.Join(outputDir, "sub", "file.txt"))
+	if err != nil || string(buf) != "foo\n" {
+		t.Errorf("expected file in output directory, but got %q (err = %v)", string(buf), err)
+	}
+	if _, err := os.Stat("sub"); !os.IsNotExist(err) {
+		t.Errorf("expected working directory to be untouched, but got err = %v", err)
+	}
+
+	if err := RemoveFile("sub"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "sub")); !os.IsNotExist(err) {
+		t.Errorf("expected directory to be removed, but got err = %v", err)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"fmt"
 	"maps"
-	"os"
 	"slices"
 	"strings"
 	"text/template"
@@ -47,9 +46,10 @@ func WriteFileFromTemplate(fileName, templateCode string, data map[string]any) e
 }
 
 // WriteFile is like os.WriteFile, but it also writes a debug log about which file is being written.
+// The file is written through the current OutputFS (see SetOutput).
 func WriteFile(fileName string, contents []byte) error {
 	logg.Debug("-> writing file %s", fileName)
-	return os.WriteFile(fileName, contents, 0o666)
+	return output.WriteFile(fileName, contents)
 }
 
 // RawString is a string type that marshals into a plain (unquoted) YAML scalar.
@@ -6,17 +6,16 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/sapcc/go-api-declarations/bininfo"
 	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/must"
 	"github.com/spf13/pflag"
-	"go.yaml.in/yaml/v3"
 
 	"github.com/sapcc/go-makefile-maker/internal/core"
 	"github.com/sapcc/go-makefile-maker/internal/dockerfile"
 	"github.com/sapcc/go-makefile-maker/internal/envrc"
+	"github.com/sapcc/go-makefile-maker/internal/generator"
 	"github.com/sapcc/go-makefile-maker/internal/ghworkflow"
 	"github.com/sapcc/go-makefile-maker/internal/golang"
 	"github.com/sapcc/go-makefile-maker/internal/golangcilint"
@@ -24,55 +23,102 @@ import (
 	"github.com/sapcc/go-makefile-maker/internal/hyperspace"
 	"github.com/sapcc/go-makefile-maker/internal/makefile"
 	"github.com/sapcc/go-makefile-maker/internal/nix"
+	"github.com/sapcc/go-makefile-maker/internal/printconfig"
 	"github.com/sapcc/go-makefile-maker/internal/renovate"
 	"github.com/sapcc/go-makefile-maker/internal/reuse"
+	"github.com/sapcc/go-makefile-maker/internal/scaffold"
+	"github.com/sapcc/go-makefile-maker/internal/schema"
+	"github.com/sapcc/go-makefile-maker/internal/toolchecksums"
 	"github.com/sapcc/go-makefile-maker/internal/typos"
+	"github.com/sapcc/go-makefile-maker/internal/util"
 )
 
+const usageText = `Usage:
+  go-makefile-maker [flags]    render all files as described by Makefile.maker.yaml
+  go-makefile-maker init       write an initial Makefile.maker.yaml based on the contents of the repository
+  go-makefile-maker print-config [--format yaml|json]
+                               print the effective configuration, including all defaults and derived values
+  go-makefile-maker schema     print the JSON schema for Makefile.maker.yaml
+
+Flags:
+`
+
 func main() {
 	bininfo.HandleVersionArgument()
 
 	var flags struct {
 		AutoupdateDeps   bool
 		AutoupdateConfig golang.AutoupdateConfiguration
+		Check            bool
+		Diff             bool
+		Format           string
+		OutputDir        string
 		ShowHelp         bool
+		UpdateChecksums  bool
 	}
 	pflag.BoolVar(&flags.AutoupdateDeps, "autoupdate-deps", false, "try to autoupdate dependencies according to the golang.autoupdateDependencies config section (if enabled)")
 	pflag.StringArrayVar(&flags.AutoupdateConfig.ExtraDependencySets, "additional-autoupdateable-dependencies", nil, "path(s) to go.mod files of other projects; any dependencies in those will be considered for --autoupdate-deps")
+	pflag.BoolVar(&flags.Check, "check", false, "do not write any files, but fail if any generated files are out of date")
+	pflag.BoolVar(&flags.Diff, "diff", false, "do not write any files, but print a unified diff for each generated file that would be changed")
+	pflag.StringVar(&flags.OutputDir, "output-dir", "", "write all generated files into this directory instead of into the working directory")
+	pflag.StringVar(&flags.Format, "format", "yaml", "output format for print-config (yaml or json)")
+	pflag.BoolVar(&flags.UpdateChecksums, "update-tool-checksums", false, "(for go-makefile-maker maintainers) add the checksums of the default versions of shellcheck and typos to "+core.ToolChecksumsPath)
 	pflag.BoolVar(&logg.ShowDebug, "debug", false, "print debug logs")
 	pflag.BoolVar(&flags.ShowHelp, "help", false, "print this message")
 	pflag.Parse()
 	if flags.ShowHelp {
-		fmt.Print("Usage of go-makefile-maker:\n", pflag.CommandLine.FlagUsages())
+		fmt.Print(usageText, pflag.CommandLine.FlagUsages())
 		return
 	}
 
-	logg.Debug("reading Makefile.maker.yaml")
-	file := must.Return(os.Open("Makefile.maker.yaml"))
-	var cfg core.Configuration
-	dec := yaml.NewDecoder(file)
-	dec.KnownFields(true)
-	must.Succeed(dec.Decode(&cfg))
-	must.Succeed(file.Close())
-	cfg.Validate()
-
-	// The github.com/ prefix is just a safeguard to avoid false positives when the metadata.url is not complete.
-	if cfg.GitHubWorkflow != nil && !strings.Contains(cfg.Metadata.URL, "github.com/") {
-		cfg.GitHubWorkflow.IsSelfHostedRunner = true
-		if strings.Contains(cfg.Metadata.URL, "/sap-cloud-infrastructure/") {
-			cfg.GitHubWorkflow.IsSugarRunner = true
-		}
+	if flags.UpdateChecksums {
+		toolchecksums.Update()
+		return
+	}
+
+	if pflag.CommandLine.Changed("format") && pflag.Arg(0) != "print-config" {
+		logg.Fatal("--format can only be used with print-config")
+	}
+	switch pflag.Arg(0) {
+	case "":
+		// default operation: render all files as described by Makefile.maker.yaml
+	case "init":
+		scaffold.WriteConfig()
+		return
+	case "print-config":
+		printconfig.Print(flags.Format)
+		return
+	case "schema":
+		fmt.Print(string(schema.Generate()))
+		return
+	default:
+		logg.Fatal("unknown subcommand: %q (see --help for usage)", pflag.Arg(0))
+	}
+	if flags.AutoupdateDeps && (flags.Check || flags.Diff || flags.OutputDir != "") {
+		logg.Fatal("--autoupdate-deps cannot be combined with --check, --diff or --output-dir")
+	}
+	if flags.OutputDir != "" && (flags.Check || flags.Diff) {
+		logg.Fatal("--output-dir cannot be combined with --check or --diff")
 	}
 
+	// In check or diff mode, all generated files are collected in memory and compared to the working directory at the end.
+	var memoryFS *util.MemoryFS
+	switch {
+	case flags.Check || flags.Diff:
+		memoryFS = util.NewMemoryFS()
+		util.SetOutput(memoryFS)
+	case flags.OutputDir != "":
+		util.SetOutput(util.NewDirFS(flags.OutputDir))
+	}
+
+	cfg, doc := core.ReadConfiguration()
+	cfg.DetectEnvironment()
+
 	if cfg.Golang.SetGoModVersion {
 		logg.Debug("checking Go version in go.mod")
 		golang.SetGoVersionInGoMod()
 	}
 
-	if fs, err := os.Stat("vendor/modules.txt"); err == nil && fs != nil {
-		cfg.Golang.EnableVendoring = true
-	}
-
 	if flags.AutoupdateDeps && cfg.Golang.AutoupdateDependencies.Enabled {
 		logg.Debug("autoupdating library dependencies")
 		golang.AutoupdateDependencies(cfg.Golang, flags.AutoupdateConfig)
@@ -82,72 +128,63 @@ func main() {
 	logg.Debug("reading go.mod")
 	sr := golang.Scan()
 
-	renderGoreleaserConfig := (cfg.GoReleaser.CreateConfig.IsNone() && cfg.GitHubWorkflow != nil && cfg.GitHubWorkflow.Release.Enabled.UnwrapOr(false)) || cfg.GoReleaser.ShouldCreateConfig()
-
-	// Render shell.nix file
-	nix.RenderShell(cfg, sr, renderGoreleaserConfig)
+	generators := allGenerators()
+	core.FatalOnErrors(generators.Validate(cfg, sr, doc))
+	generators.Run(cfg, sr)
 
-	// Render Makefile
-	if cfg.Makefile.Enabled.UnwrapOr(true) {
-		logg.Debug("rendering Makefile")
-		for _, bin := range cfg.Binaries {
-			if !strings.HasPrefix(bin.FromPackage, ".") {
-				logg.Fatal("binaries[].fromPackage must begin with a dot, %q is not allowed!", bin.FromPackage)
-			}
-		}
-		makefile.Render(cfg, sr)
+	if flags.Diff {
+		printDiffs(memoryFS)
 	}
-
-	// Render Dockerfile
-	if cfg.Dockerfile.Enabled {
-		logg.Debug("rendering Dockerfile")
-		dockerfile.RenderConfig(cfg, sr)
+	if flags.Check {
+		reportOutdatedFiles(memoryFS)
 	}
+}
 
-	// Render golangci-lint config file
-	if cfg.GolangciLint.CreateConfig {
-		logg.Debug("rendering golangci-lint configuration")
-		golangcilint.RenderConfig(cfg, sr)
-	}
+// allGenerators returns a registry with all generators.
+func allGenerators() *generator.Registry {
+	var generators generator.Registry
+	generators.Add(
+		nix.Generator{},
+		makefile.Generator{},
+		dockerfile.Generator{},
+		golangcilint.Generator{},
+		goreleaser.Generator{},
+		ghworkflow.Generator{},
+		hyperspace.Generator{},
+		envrc.Generator{},
+		renovate.Generator{},
+		reuse.Generator{},
+		typos.Generator{},
+	)
+	return &generators
+}
 
-	// Render Goreleaser config file
-	if renderGoreleaserConfig {
-		logg.Debug("rendering goreleaser configuration")
-		goreleaser.RenderConfig(cfg)
+// printDiffs prints a unified diff for each file that would be changed by rendering into the given MemoryFS.
+func printDiffs(memoryFS *util.MemoryFS) {
+	for _, change := range must.Return(memoryFS.Changes()) {
+		fmt.Print(util.UnifiedDiff(change.Path, change.OldContents.UnwrapOr(nil), change.NewContents.UnwrapOr(nil)))
 	}
+}
 
-	// Render GitHub workflows
-	var ghworkflowPaths []string
-	if cfg.GitHubWorkflow != nil {
-		logg.Debug("rendering GitHub Actions workflows")
-		ghworkflowPaths = ghworkflow.Render(cfg, sr)
+// reportOutdatedFiles lists all files that would be changed by rendering into the given MemoryFS,
+// and exits non-zero if there are any.
+func reportOutdatedFiles(memoryFS *util.MemoryFS) {
+	changes := must.Return(memoryFS.Changes())
+	if len(changes) == 0 {
+		logg.Debug("all generated files are up to date")
+		return
 	}
 
-	// Render Hyperspace config file
-	logg.Debug("rendering hyperspace configuration")
-	hyperspace.RenderConfig(cfg)
-
-	// Render envrc file
-	envrc.RenderEnvRc(cfg)
-
-	// Render Renovate config
-	if cfg.Renovate.Enabled {
-		logg.Debug("rendering Renovate configuration")
-		if cfg.Renovate.GoVersion == "" {
-			cfg.Renovate.GoVersion = sr.GoVersionMajorMinor
+	fmt.Fprintln(os.Stderr, "The following generated files are out of date:")
+	for _, change := range changes {
+		switch {
+		case change.OldContents.IsNone():
+			fmt.Fprintf(os.Stderr, "  %s (missing)\n", change.Path)
+		case change.NewContents.IsNone():
+			fmt.Fprintf(os.Stderr, "  %s (obsolete)\n", change.Path)
+		default:
+			fmt.Fprintf(os.Stderr, "  %s\n", change.Path)
 		}
-		renovate.RenderConfig(cfg, sr, ghworkflowPaths)
-	}
-
-	// Render REUSE config file
-	if cfg.Reuse.IsEnabled() {
-		logg.Debug("rendering REUSE configuration")
-		reuse.RenderConfig(cfg, sr)
-	}
-
-	// Render typos config file
-	if cfg.Typos.IsEnabled() {
-		logg.Debug("rendering typos configuration")
-		typos.RenderConfig(cfg)
 	}
+	logg.Fatal("%d generated files are out of date, please run go-makefile-maker to update them", len(changes))
 }
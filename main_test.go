@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sapcc/go-makefile-maker/internal/core"
+	"github.com/sapcc/go-makefile-maker/internal/golang"
+	"github.com/sapcc/go-makefile-maker/internal/util"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/golden instead of comparing against them")
+
+// TestGoldenFiles renders each Makefile.maker.yaml below testdata/golden
+// and compares the result against the files in the "output" directory next to it.
+// To update the expected files after an intentional change, run `go test . -update`.
+func TestGoldenFiles(t *testing.T) {
+	caseDirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, caseDir := range caseDirs {
+		t.Run(filepath.Base(caseDir), func(t *testing.T) {
+			buf, err := os.ReadFile(filepath.Join(caseDir, core.ConfigurationPath))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, doc, errs := core.ParseConfiguration(buf)
+			sr := golang.ScanResult{
+				ModulePath:          "github.com/example/" + filepath.Base(caseDir),
+				GoVersion:           "1.26.0",
+				GoVersionMajorMinor: "1.26",
+				HasBinInfo:          true,
+			}
+
+			generators := allGenerators()
+			errs = append(errs, generators.Validate(cfg, sr, doc)...)
+			for _, err := range errs {
+				t.Fatal(err.Error())
+			}
+
+			outputDir := t.TempDir()
+			defer util.SetOutput(util.SetOutput(util.NewDirFS(outputDir)))
+			generators.Run(cfg, sr)
+
+			expectedDir := filepath.Join(caseDir, "output")
+			if *updateGolden {
+				err := os.RemoveAll(expectedDir)
+				if err == nil {
+					err = os.CopyFS(expectedDir, os.DirFS(outputDir))
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			actual := readTree(t, outputDir)
+			expected := readTree(t, expectedDir)
+			for _, path := range slices.Sorted(maps.Keys(expected)) {
+				actualContents, exists := actual[path]
+				if !exists {
+					t.Errorf("expected %s to be generated, but it was not", path)
+				} else if !bytes.Equal(actualContents, expected[path]) {
+					t.Errorf("%s differs from the golden file (run `go test . -update` to update it):\n%s",
+						path, util.UnifiedDiff(path, expected[path], actualContents))
+				}
+			}
+			for _, path := range slices.Sorted(maps.Keys(actual)) {
+				if _, exists := expected[path]; !exists {
+					t.Errorf("%s was generated unexpectedly", path)
+				}
+			}
+		})
+	}
+}
+
+// readTree returns the contents of all files below the given directory, keyed by their relative path.
+func readTree(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	result := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		result[filepath.ToSlash(relPath)] = buf
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
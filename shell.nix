@@ -7,15 +7,15 @@ with pkgs;
 
 mkShell {
   nativeBuildInputs = [
-    addlicense
-    go-licence-detector
+    addlicense # tools.addlicense: v1.2.0
+    go-licence-detector # tools.goLicenceDetector: v0.7.0
     go_1_26
-    golangci-lint
-    gotools # goimports
+    golangci-lint # tools.golangciLint: v2.12.2
+    gotools # goimports, tools.goimports: v0.38.0
     renovate
     renovate
     reuse
-    typos
+    typos # tools.typos: v1.38.1
     # keep this line if you use bash
     bashInteractive
   ];
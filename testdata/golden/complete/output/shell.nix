@@ -0,0 +1,25 @@
+# SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company
+# SPDX-License-Identifier: Apache-2.0
+
+{ pkgs ? import <nixpkgs> { } }:
+
+with pkgs;
+
+mkShell {
+  nativeBuildInputs = [
+    addlicense # tools.addlicense: v1.2.0
+    go-licence-detector # tools.goLicenceDetector: v0.7.0
+    go_1_26
+    golangci-lint # tools.golangciLint: v2.11.0
+    goreleaser
+    gotools # goimports, tools.goimports: v0.38.0
+    postgresql_18
+    redis
+    renovate
+    reuse
+    syft
+    typos # tools.typos: v1.36.0
+    # keep this line if you use bash
+    bashInteractive
+  ];
+}
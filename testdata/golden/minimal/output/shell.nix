@@ -0,0 +1,19 @@
+# SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company
+# SPDX-License-Identifier: Apache-2.0
+
+{ pkgs ? import <nixpkgs> { } }:
+
+with pkgs;
+
+mkShell {
+  nativeBuildInputs = [
+    addlicense # tools.addlicense: v1.2.0
+    go-licence-detector # tools.goLicenceDetector: v0.7.0
+    go_1_26
+    gotools # goimports, tools.goimports: v0.38.0
+    reuse
+    typos # tools.typos: v1.38.1
+    # keep this line if you use bash
+    bashInteractive
+  ];
+}